@@ -0,0 +1,348 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+const (
+	defaultSource        = "default"
+	clusterOptionsSource = "--clusters/--enabled"
+)
+
+func newConfigCmd(rootCmd *cloudTestCmd) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Configuration file operations",
+	}
+
+	output := "yaml"
+	dumpCmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Print effective configuration",
+		Long: `Print configuration with all imports, profile, overrides and command line options applied.
+Every value is annotated with a file or an option it came from, values without annotation came from same source as
+their parent section. Values of environment variables listed in env-check of providers are masked.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			out, err := dumpConfig(rootCmd.cmdArguments, output)
+			if err != nil {
+				logrus.Errorf("%v", err)
+				os.Exit(1)
+			}
+			fmt.Print(out)
+		},
+	}
+	dumpCmd.Flags().StringVarP(&output, "output", "o", output, "Output format, yaml or json")
+	configCmd.AddCommand(dumpCmd)
+
+	configCmd.AddCommand(&cobra.Command{
+		Use:   "migrate [file...]",
+		Short: "Upgrade configuration files to current version",
+		Long: `Rewrite configuration files in place using current configuration layout, every change is printed as a warning.
+A file passed with --config or ` + defaultConfigFile + ` is used if no files are passed. Comments are not preserved.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				args = []string{rootCmd.cmdArguments.providerConfig}
+				if args[0] == "" {
+					args[0] = defaultConfigFile
+				}
+			}
+			for _, fileName := range args {
+				if err := migrateConfigFile(fileName); err != nil {
+					logrus.Errorf("%v", err)
+					os.Exit(1)
+				}
+			}
+		},
+	})
+
+	return configCmd
+}
+
+func newSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print JSON schema of cloud_test configuration file",
+		Long:  `Print JSON schema of configuration file, could be used by editors and pre-commit hooks to validate configuration.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			schema, err := config.Schema()
+			if err != nil {
+				logrus.Errorf("failed to generate schema: %v", err)
+				os.Exit(1)
+			}
+			fmt.Print(string(schema))
+		},
+	}
+}
+
+// migrateConfigFile - upgrade configuration file to current version in place.
+func migrateConfigFile(fileName string) error {
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return err
+	}
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	migrated, warnings, err := config.MigrateContent(content, true)
+	if err != nil {
+		return errors.Wrapf(err, "failed to migrate %s", fileName)
+	}
+	if bytes.Equal(migrated, content) {
+		logrus.Infof("%s is up to date", fileName)
+		return nil
+	}
+	for _, w := range warnings {
+		logrus.Warnf("%s: %s", fileName, w)
+	}
+	if err = ioutil.WriteFile(fileName, migrated, info.Mode()); err != nil {
+		return err
+	}
+	logrus.Infof("%s is migrated to version %s", fileName, config.CurrentVersion)
+	return nil
+}
+
+// dumpConfig - return effective configuration in passed format.
+func dumpConfig(arguments *Arguments, format string) (string, error) {
+	testConfig, sources, err := loadEffectiveConfig(arguments)
+	var problems configErrors
+	if !appendConfigErrors(&problems, err) {
+		return "", err
+	}
+	if len(problems) > 0 {
+		logrus.Warnf("%v", problems)
+	}
+
+	var envCheck []string
+	for _, cl := range testConfig.Providers {
+		if enabled := isProviderEnabled(cl, arguments); enabled != cl.Enabled {
+			cl.Enabled = enabled
+			sources["providers."+cl.Name+".enabled"] = clusterOptionsSource
+		}
+		envCheck = append(envCheck, cl.EnvCheck...)
+	}
+
+	out, err := yaml.Marshal(testConfig)
+	if err != nil {
+		return "", err
+	}
+	var root yaml.MapSlice
+	if err = yaml.Unmarshal(out, &root); err != nil {
+		return "", err
+	}
+	if !arguments.instanceOptions.NoMaskParameters {
+		root = maskConfigValues(root, envCheck).(yaml.MapSlice)
+	}
+
+	switch format {
+	case "yaml":
+		dumper := &yamlDumper{sources: sources}
+		if arguments.count >= 0 {
+			dumper.out.WriteString(fmt.Sprintf("# --count: %d\n", arguments.count))
+		}
+		for _, run := range arguments.run {
+			dumper.out.WriteString(fmt.Sprintf("# --run: %s\n", run))
+		}
+		for _, skip := range arguments.skip {
+			dumper.out.WriteString(fmt.Sprintf("# --skip: %s\n", skip))
+		}
+		if err = dumper.writeMap(root, "", nil, "", ""); err != nil {
+			return "", err
+		}
+		return dumper.out.String(), nil
+	case "json":
+		result := map[string]interface{}{
+			"config":  jsonValue(root),
+			"sources": sources,
+		}
+		if arguments.count >= 0 {
+			result["count"] = arguments.count
+		}
+		if len(arguments.run) > 0 {
+			result["run"] = arguments.run
+		}
+		if len(arguments.skip) > 0 {
+			result["skip"] = arguments.skip
+		}
+		out, err = json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(out) + "\n", nil
+	}
+	return "", errors.Errorf("unknown output format %q, expected yaml or json", format)
+}
+
+// maskConfigValues - mask values of passed environment variables in all strings of yaml document.
+func maskConfigValues(value interface{}, envNames []string) interface{} {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		for idx := range v {
+			v[idx].Value = maskConfigValues(v[idx].Value, envNames)
+		}
+	case []interface{}:
+		for idx := range v {
+			v[idx] = maskConfigValues(v[idx], envNames)
+		}
+	case string:
+		return utils.MaskEnvValues(v, envNames)
+	}
+	return value
+}
+
+// jsonValue - convert yaml document to value could be marshaled to json.
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		result := map[string]interface{}{}
+		for _, item := range v {
+			result[fmt.Sprint(item.Key)] = jsonValue(item.Value)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for idx, item := range v {
+			result[idx] = jsonValue(item)
+		}
+		return result
+	}
+	return value
+}
+
+// yamlDumper - writes yaml document with a comment of source for every value with source different from its parent.
+type yamlDumper struct {
+	out     strings.Builder
+	sources configSources
+}
+
+// writeMap - write map items, paths are alternative paths of map, itemComment is added to first line.
+func (d *yamlDumper) writeMap(node yaml.MapSlice, indent string, paths []string, parentSource, itemComment string) error {
+	for idx, item := range node {
+		key := fmt.Sprint(item.Key)
+		var itemPaths []string
+		for _, p := range paths {
+			itemPaths = append(itemPaths, p+"."+key)
+		}
+		if len(paths) == 0 {
+			itemPaths = []string{key}
+		}
+
+		source := d.sources.lookup(itemPaths...)
+		if source == "" && len(paths) == 0 && !isListSection(key) {
+			source = defaultSource
+		}
+		comment := ""
+		if source != "" && source != parentSource {
+			comment = " # " + source
+		} else {
+			source = parentSource
+		}
+		if idx == 0 && comment == "" {
+			comment = itemComment
+		}
+
+		switch v := item.Value.(type) {
+		case yaml.MapSlice:
+			if len(v) > 0 {
+				d.out.WriteString(indent + key + ":" + comment + "\n")
+				if err := d.writeMap(v, indent+"  ", itemPaths, source, ""); err != nil {
+					return err
+				}
+				continue
+			}
+		case []interface{}:
+			if isMapList(v) {
+				d.out.WriteString(indent + key + ":" + comment + "\n")
+				for itemIdx, listItem := range v {
+					if err := d.writeListItem(listItem.(yaml.MapSlice), indent, itemPaths, itemIdx, source); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+		}
+		if err := d.writeValue(indent, key, item.Value, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeListItem - write list item, item is addressed by its name or by its index.
+func (d *yamlDumper) writeListItem(item yaml.MapSlice, indent string, paths []string, idx int, parentSource string) error {
+	var itemPaths []string
+	for _, p := range paths {
+		for _, field := range item {
+			if field.Key == "name" && fmt.Sprint(field.Value) != "" {
+				itemPaths = append(itemPaths, p+"."+fmt.Sprint(field.Value))
+			}
+		}
+		itemPaths = append(itemPaths, fmt.Sprintf("%s.%d", p, idx))
+	}
+	source := d.sources.lookup(itemPaths...)
+	comment := ""
+	if source != "" && source != parentSource {
+		comment = " # " + source
+	} else {
+		source = parentSource
+	}
+
+	sub := &yamlDumper{sources: d.sources}
+	if err := sub.writeMap(item, indent+"  ", itemPaths, source, comment); err != nil {
+		return err
+	}
+	d.out.WriteString(indent + "- " + strings.TrimPrefix(sub.out.String(), indent+"  "))
+	return nil
+}
+
+func (d *yamlDumper) writeValue(indent, key string, value interface{}, comment string) error {
+	out, err := yaml.Marshal(yaml.MapSlice{{Key: key, Value: value}})
+	if err != nil {
+		return err
+	}
+	for idx, line := range strings.Split(strings.TrimSuffix(string(out), "\n"), "\n") {
+		if idx == 0 {
+			line += comment
+		}
+		d.out.WriteString(indent + line + "\n")
+	}
+	return nil
+}
+
+func isMapList(items []interface{}) bool {
+	for _, item := range items {
+		if _, ok := item.(yaml.MapSlice); !ok {
+			return false
+		}
+	}
+	return len(items) > 0
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+var dumpConfigFiles = map[string]string{
+	"cloudtest.yaml": `---
+import:
+  - executions.yaml
+providers:
+  - name: "a_provider"
+    kind: "shell"
+    instances: 1
+    env-check:
+      - CLOUDTEST_DUMP_TOKEN
+    env:
+      - TOKEN=dump-secret
+`,
+	"executions.yaml": `---
+executions:
+  - name: "simple"
+    timeout: 60
+retest:
+  count: 2
+`,
+}
+
+func TestDumpConfigYaml(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir := writeConfigFiles(t, dumpConfigFiles)
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(os.Setenv("CLOUDTEST_DUMP_TOKEN", "dump-secret")).Should(gomega.BeNil())
+	defer func() { _ = os.Unsetenv("CLOUDTEST_DUMP_TOKEN") }()
+
+	rootFile, importFile := filepath.Join(tmpDir, "cloudtest.yaml"), filepath.Join(tmpDir, "executions.yaml")
+	out, err := dumpConfig(&Arguments{
+		providerConfig: rootFile,
+		clusters:       []string{"a_provider"},
+		overrides:      []string{"executions.simple.timeout=10m"},
+		count:          5,
+	}, "yaml")
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(out).Should(gomega.HavePrefix("# --count: 5\n"))
+	g.Expect(out).Should(gomega.ContainSubstring("- name: a_provider # " + rootFile + "\n"))
+	g.Expect(out).Should(gomega.ContainSubstring("  enabled: true # --clusters/--enabled\n"))
+	g.Expect(out).Should(gomega.ContainSubstring("  - TOKEN=****\n"))
+	g.Expect(out).ShouldNot(gomega.ContainSubstring("dump-secret"))
+	g.Expect(out).Should(gomega.ContainSubstring("- source: # " + importFile + "\n"))
+	g.Expect(out).Should(gomega.ContainSubstring("  timeout: 10m0s # --set\n"))
+	g.Expect(out).Should(gomega.ContainSubstring("retest: # " + importFile + "\n"))
+	g.Expect(out).Should(gomega.ContainSubstring("statistics: # default\n"))
+}
+
+func TestDumpConfigJson(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir := writeConfigFiles(t, dumpConfigFiles)
+	defer utils.ClearFolder(tmpDir, false)
+
+	out, err := dumpConfig(&Arguments{providerConfig: filepath.Join(tmpDir, "cloudtest.yaml"), count: -1}, "json")
+	g.Expect(err).Should(gomega.BeNil())
+
+	var result struct {
+		Config struct {
+			Executions []struct {
+				Name    string `json:"name"`
+				Timeout string `json:"timeout"`
+			} `json:"executions"`
+		} `json:"config"`
+		Sources map[string]string `json:"sources"`
+	}
+	g.Expect(json.Unmarshal([]byte(out), &result)).Should(gomega.BeNil())
+	g.Expect(result.Config.Executions[0].Timeout).Should(gomega.Equal("2m0s"))
+	g.Expect(result.Sources["executions.simple"]).Should(gomega.Equal(filepath.Join(tmpDir, "executions.yaml")))
+
+	_, err = dumpConfig(&Arguments{providerConfig: filepath.Join(tmpDir, "cloudtest.yaml")}, "xml")
+	g.Expect(err).Should(gomega.MatchError(`unknown output format "xml", expected yaml or json`))
+}
+
+func TestMigrateConfigFile(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"cloudtest.yaml": `---
+version: 1.0
+providers:
+  - name: "a_provider"
+    scripts:
+      start: |
+        echo one
+        echo two
+executions:
+  - name: "simple"
+    timeout: 60
+    on_fail: echo failed
+`,
+	})
+	defer utils.ClearFolder(tmpDir, false)
+	configFile := filepath.Join(tmpDir, "cloudtest.yaml")
+
+	// Legacy layout is loaded with deprecation warnings, scripts are kept as is.
+	cfg, _, err := loadConfig(configFile)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(cfg.Executions[0].OnFail.String()).Should(gomega.Equal("echo failed"))
+	g.Expect(cfg.Executions[0].Timeout.Duration()).Should(gomega.Equal(2 * time.Minute))
+	g.Expect(cfg.Providers[0].Scripts["start"].IsPlain()).Should(gomega.BeTrue())
+
+	g.Expect(migrateConfigFile(configFile)).Should(gomega.BeNil())
+	content, err := ioutil.ReadFile(configFile)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(string(content)).Should(gomega.ContainSubstring(`version: "1.1"`))
+	g.Expect(string(content)).Should(gomega.ContainSubstring("on-fail: echo failed"))
+	g.Expect(string(content)).Should(gomega.ContainSubstring("timeout: 2m0s"))
+	g.Expect(string(content)).Should(gomega.ContainSubstring("- run: echo one\n"))
+
+	cfg, _, err = loadConfig(configFile)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(cfg.Executions[0].OnFail.String()).Should(gomega.Equal("echo failed"))
+	g.Expect(len(cfg.Providers[0].Scripts["start"])).Should(gomega.Equal(2))
+}
+
+func TestLoadLegacyConfigProblemLines(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"cloudtest.yaml": `---
+version: 1.0
+providers:
+  - name: "a_provider"
+    timeout: 60
+    scripts:
+      start: |
+        echo one
+
+        echo two
+executions:
+  - name: "simple"
+    on_fail: echo failed
+    cluster-env:
+      - KUBECONFIG
+`,
+	})
+	defer utils.ClearFolder(tmpDir, false)
+
+	// Problems are reported with lines of file, not of migrated content.
+	_, _, err := loadConfig(filepath.Join(tmpDir, "cloudtest.yaml"))
+	g.Expect(err).Should(gomega.MatchError(configErrors{
+		filepath.Join(tmpDir, "cloudtest.yaml") + ":14: field cluster-env not found in type config.Execution",
+	}))
+}
+
+func TestLoadUnsupportedVersion(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir := writeConfigFiles(t, map[string]string{"cloudtest.yaml": "version: 3.0\n"})
+	defer utils.ClearFolder(tmpDir, false)
+
+	_, _, err := loadConfig(filepath.Join(tmpDir, "cloudtest.yaml"))
+	g.Expect(err).Should(gomega.MatchError(configErrors{
+		filepath.Join(tmpDir, "cloudtest.yaml") + `: unsupported configuration version "3.0", supported versions are 1.0, 1.1`,
+	}))
+}
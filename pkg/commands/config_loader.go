@@ -0,0 +1,329 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+// configLoader - loads configuration file with all nested imports.
+//
+// Imports are resolved relative to the folder of the file declaring them, a file imported more than once is loaded
+// only once. Providers, executions and health checks of all files are concatenated, items of the importing file go
+// first. All other sections (reporting, retest, statistics, timeout, etc.) are taken as a whole from a single file:
+// the importing file overrides its imports, and a later import overrides an earlier one.
+type configLoader struct {
+	loading      []string                             // A stack of files being loaded, used to detect import cycles.
+	loaded       map[string]bool                      // All files already loaded.
+	sources      configSources                        // A file every provider, execution and top level section is taken from.
+	healthChecks map[*config.HealthCheckConfig]string // A file every health check is taken from.
+	problems     configErrors
+}
+
+// configSources - a configuration path, like "executions.basic.timeout", to a file or option it value came from.
+type configSources map[string]string
+
+// lookup - return a source of first found path.
+func (s configSources) lookup(paths ...string) string {
+	for _, p := range paths {
+		if source, ok := s[p]; ok {
+			return source
+		}
+	}
+	return ""
+}
+
+func newConfigLoader() *configLoader {
+	return &configLoader{
+		loaded:       map[string]bool{},
+		sources:      configSources{},
+		healthChecks: map[*config.HealthCheckConfig]string{},
+	}
+}
+
+// loadConfig - read and strictly parse configuration file and process its imports, return configuration
+// and sources of its values. In case of configErrors returned, configuration is loaded but has problems.
+func loadConfig(fileName string) (*config.CloudTestConfig, configSources, error) {
+	if fileName == "" {
+		fileName = defaultConfigFile
+	}
+	testConfig := config.NewCloudTestConfig()
+	loader := newConfigLoader()
+	if _, err := loader.load(testConfig, fileName); err != nil {
+		logrus.Errorf("Failed to load config file %v", err)
+		return nil, nil, err
+	}
+	for idx, hc := range testConfig.HealthCheck {
+		loader.sources[fmt.Sprintf("health-check.%d", idx)] = loader.healthChecks[hc]
+	}
+	if len(loader.problems) > 0 {
+		return testConfig, loader.sources, loader.problems
+	}
+	return testConfig, loader.sources, nil
+}
+
+// load - parse configuration file into testConfig and process its imports, return top level keys defined.
+// Nil keys are returned if file is already loaded.
+func (l *configLoader) load(testConfig *config.CloudTestConfig, fileName string) (map[string]bool, error) {
+	absName, err := filepath.Abs(fileName)
+	if err != nil {
+		return nil, err
+	}
+	for idx, f := range l.loading {
+		if f == absName {
+			l.problems.add("import cycle detected: %s", strings.Join(append(l.loading[idx:], absName), " -> "))
+			return nil, nil
+		}
+	}
+	if l.loaded[absName] {
+		logrus.Infof("configuration file %s is already imported, skipping", fileName)
+		return nil, nil
+	}
+	l.loaded[absName] = true
+	l.loading = append(l.loading, absName)
+	defer func() { l.loading = l.loading[:len(l.loading)-1] }()
+
+	configFileContent, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read config file %s", fileName)
+	}
+	keys := map[string]interface{}{}
+	if err = yaml.Unmarshal(configFileContent, &keys); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse configuration file %s", fileName)
+	}
+	migrated := l.migrate(configFileContent, fileName)
+	err = parseConfig(testConfig, fileName, migrated)
+	if _, ok := err.(configErrors); ok && !bytes.Equal(migrated, configFileContent) {
+		// Lines of migrated content differ from ones of file, so problems are reported for original content.
+		err = parseLegacyConfig(fileName, configFileContent, err)
+	}
+	if !appendConfigErrors(&l.problems, err) {
+		return nil, err
+	}
+	definedKeys := map[string]bool{}
+	for key := range keys {
+		definedKeys[key] = true
+	}
+
+	for _, cl := range testConfig.Providers {
+		l.define("provider", "providers."+cl.Name, fileName)
+	}
+	for _, exec := range testConfig.Executions {
+		l.define("execution", "executions."+exec.Name, fileName)
+	}
+	for _, hc := range testConfig.HealthCheck {
+		l.healthChecks[hc] = fileName
+	}
+
+	if err = l.performImport(testConfig, definedKeys, fileName); err != nil {
+		return nil, err
+	}
+	// Sections defined in this file override ones of imports.
+	for key := range keys {
+		if !isListSection(key) {
+			l.sources[key] = fileName
+		}
+	}
+	return definedKeys, nil
+}
+
+// migrate - upgrade configuration file content to current version, deprecation warnings are printed. Scripts are not
+// restructured, only config migrate splits them into steps.
+func (l *configLoader) migrate(configFileContent []byte, fileName string) []byte {
+	migrated, warnings, err := config.MigrateContent(configFileContent, false)
+	if err != nil {
+		l.problems.add("%s: %v", fileName, err)
+		return configFileContent
+	}
+	if len(warnings) == 0 {
+		return configFileContent
+	}
+	for _, w := range warnings {
+		logrus.Warnf("%s: %s", fileName, w)
+	}
+	logrus.Warnf("%s: deprecated configuration layout is used, run 'cloud_test config migrate %s' to upgrade it to version %s",
+		fileName, fileName, config.CurrentVersion)
+	return migrated
+}
+
+// define - register a named item, a problem is reported if item is already defined.
+func (l *configLoader) define(kind, key, fileName string) {
+	name := strings.SplitN(key, ".", 2)[1]
+	if name == "" {
+		return
+	}
+	if source, ok := l.sources[key]; ok {
+		if source == fileName {
+			l.problems.add("%s %q is defined more than once in %s", kind, name, fileName)
+		} else {
+			l.problems.add("%s %q is defined in both %s and %s", kind, name, source, fileName)
+		}
+		return
+	}
+	l.sources[key] = fileName
+}
+
+// performImport - load all imports of testConfig, declared in fileName, and merge them into testConfig.
+func (l *configLoader) performImport(testConfig *config.CloudTestConfig, keys map[string]bool, fileName string) error {
+	files, err := l.resolveImports(testConfig.Imports, fileName)
+	if err != nil {
+		return err
+	}
+	imported := &config.CloudTestConfig{}
+	importedKeys := map[string]bool{}
+	for _, f := range files {
+		// Imported sections not set in file keep default values, same as sections of importing file.
+		importConfig := config.NewCloudTestConfig()
+		importKeys, err := l.load(importConfig, f)
+		if err != nil {
+			return err
+		}
+		if importKeys == nil {
+			continue
+		}
+		mergeConfig(imported, importedKeys, importConfig, importKeys, true)
+	}
+	mergeConfig(testConfig, keys, imported, importedKeys, false)
+	return nil
+}
+
+// resolveImports - find files matching imports, every import is a file or a folder with a file name regular expression,
+// relative to folder of fileName.
+func (l *configLoader) resolveImports(imports []string, fileName string) ([]string, error) {
+	baseDir := filepath.Dir(fileName)
+	resolve := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(baseDir, p)
+	}
+	var result []string
+	for _, imp := range imports {
+		if f := resolve(imp); utils.FileExists(f) {
+			result = append(result, f)
+			continue
+		}
+		dir, pattern := filepath.Split(imp)
+		searchDir := resolve(dir)
+		var names []string
+		files := map[string]string{}
+		for _, f := range utils.GetAllFiles(searchDir) {
+			if filepath.Clean(f) == filepath.Clean(fileName) {
+				continue
+			}
+			rel, err := filepath.Rel(searchDir, f)
+			if err != nil {
+				return nil, err
+			}
+			// Pattern is matched against file path as it is written in configuration.
+			name := filepath.Join(dir, rel)
+			names = append(names, name)
+			files[name] = f
+		}
+		matches, err := utils.FilterByPattern(names, pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid import %q in %s", imp, fileName)
+		}
+		if len(matches) == 0 {
+			l.problems.add("%s: import %q does not match any file", fileName, imp)
+		}
+		for _, m := range matches {
+			result = append(result, files[m])
+		}
+	}
+	return result, nil
+}
+
+// mergeConfig - merge source configuration into target. List sections are appended, other top level sections
+// defined in source are copied if override is passed or they are not defined in target.
+func mergeConfig(target *config.CloudTestConfig, targetKeys map[string]bool,
+	source *config.CloudTestConfig, sourceKeys map[string]bool, override bool) {
+	targetValue := reflect.ValueOf(target).Elem()
+	sourceValue := reflect.ValueOf(source).Elem()
+	for i := 0; i < targetValue.NumField(); i++ {
+		key := strings.Split(targetValue.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		switch {
+		case key == "import":
+			continue
+		case isListSection(key):
+			targetValue.Field(i).Set(reflect.AppendSlice(targetValue.Field(i), sourceValue.Field(i)))
+		case sourceKeys[key] && (override || !targetKeys[key]):
+			targetValue.Field(i).Set(sourceValue.Field(i))
+		}
+	}
+	for key := range sourceKeys {
+		targetKeys[key] = true
+	}
+}
+
+// isListSection - check if top level section items of all imported files are concatenated.
+func isListSection(key string) bool {
+	return key == "providers" || key == "executions" || key == "health-check"
+}
+
+var unknownFieldPattern = regexp.MustCompile(`: field (\S+) not found in type `)
+
+// parseLegacyConfig - strictly parse original content of legacy configuration file, fields renamed by migration are
+// not reported. Problems of migrated content are returned if none are found in original one.
+func parseLegacyConfig(fileName string, configFileContent []byte, migratedErr error) error {
+	problems, ok := parseConfig(config.NewCloudTestConfig(), fileName, configFileContent).(configErrors)
+	if !ok {
+		return migratedErr
+	}
+	var result configErrors
+	for _, problem := range problems {
+		if match := unknownFieldPattern.FindStringSubmatch(problem); match == nil || config.RenamedFields[match[1]] == "" {
+			result = append(result, problem)
+		}
+	}
+	if len(result) == 0 {
+		return migratedErr
+	}
+	return result
+}
+
+// parseConfig - strictly parse configuration file content, unknown fields are reported as configErrors with file:line.
+func parseConfig(cloudTestConfig *config.CloudTestConfig, fileName string, configFileContent []byte) error {
+	err := yaml.UnmarshalStrict(configFileContent, cloudTestConfig)
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		var problems configErrors
+		for _, msg := range typeErr.Errors {
+			problems.add("%s:%s", fileName, strings.TrimPrefix(msg, "line "))
+		}
+		return problems
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "failed to parse configuration file %s", fileName)
+		logrus.Errorf(err.Error())
+		return err
+	}
+	logrus.Infof("configuration file %s loaded successfully...", fileName)
+	return nil
+}
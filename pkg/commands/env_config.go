@@ -0,0 +1,133 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/shell"
+)
+
+const envPrefix = "CLOUDTEST"
+
+// envConfigPaths - top level configuration values could be set with CLOUDTEST_* environment variables.
+var envConfigPaths = []string{
+	"root",
+	"timeout",
+	"shuffle-enabled",
+	"reporting.junit-report",
+	"retest.count",
+	"retest.allowed-retests",
+	"retest.fail-result",
+	"statistics.enabled",
+	"statistics.interval",
+}
+
+// reservedEnvNames - environment variables set by cloudtest itself, they are not bound to flags with same names, like
+// CLOUDTEST_OUTPUT of script steps and --output flag of config dump.
+var reservedEnvNames = map[string]bool{
+	shell.OutputEnv: true,
+}
+
+// envName - return environment variable name for flag or configuration path, like noStop -> CLOUDTEST_NO_STOP
+// or reporting.junit-report -> CLOUDTEST_REPORTING_JUNIT_REPORT.
+func envName(name string) string {
+	result := strings.Builder{}
+	result.WriteString(envPrefix)
+	result.WriteRune('_')
+	for idx, r := range name {
+		switch {
+		case r == '-' || r == '.':
+			result.WriteRune('_')
+		case unicode.IsUpper(r) && idx > 0:
+			result.WriteRune('_')
+			result.WriteRune(r)
+		default:
+			result.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return result.String()
+}
+
+// envHelp - a help of environment variables, appended to description of root command.
+func envHelp() string {
+	var names []string
+	for _, p := range envConfigPaths {
+		names = append(names, "  "+envName(p)+" - "+p)
+	}
+	return `
+Every flag could be set with an environment variable named by ` + envPrefix + `_ prefix and flag name in upper snake
+case, like ` + envName("noStop") + ` for --noStop. Values of list flags are separated by spaces. ` + shell.OutputEnv + ` is
+reserved for outputs of script steps and does not set --output flag.
+Following configuration values could be set with environment variables too:
+` + strings.Join(names, "\n") + `
+A flag takes precedence over environment variable and environment variable takes precedence over configuration file,
+profile is applied before environment variables and --set overrides are applied after them.`
+}
+
+// forEachFlag - call fn for all flags of command and its sub commands.
+func forEachFlag(cmd *cobra.Command, fn func(flag *pflag.Flag)) {
+	cmd.PersistentFlags().VisitAll(fn)
+	cmd.LocalNonPersistentFlags().VisitAll(fn)
+	for _, sub := range cmd.Commands() {
+		forEachFlag(sub, fn)
+	}
+}
+
+// documentEnvironment - add environment variable name to usage of all flags.
+func documentEnvironment(cmd *cobra.Command) {
+	forEachFlag(cmd, func(flag *pflag.Flag) {
+		if !reservedEnvNames[envName(flag.Name)] {
+			flag.Usage += " [$" + envName(flag.Name) + "]"
+		}
+	})
+}
+
+// bindEnvironment - set values of flags not passed in command line from CLOUDTEST_* environment variables.
+func bindEnvironment(cmd *cobra.Command) error {
+	v := viper.New()
+	var result error
+	forEachFlag(cmd, func(flag *pflag.Flag) {
+		if result != nil || flag.Changed || reservedEnvNames[envName(flag.Name)] {
+			return
+		}
+		if result = v.BindEnv(flag.Name, envName(flag.Name)); result != nil {
+			return
+		}
+		if !v.IsSet(flag.Name) {
+			return
+		}
+		values := []string{v.GetString(flag.Name)}
+		if _, ok := flag.Value.(pflag.SliceValue); ok {
+			values = v.GetStringSlice(flag.Name)
+		}
+		for _, value := range values {
+			if err := flag.Value.Set(value); err != nil {
+				result = errors.Wrapf(err, "invalid value %q of %s", value, envName(flag.Name))
+				return
+			}
+		}
+		flag.Changed = true
+	})
+	return result
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+func setEnv(t *testing.T, values map[string]string) func() {
+	for key, value := range values {
+		if err := os.Setenv(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return func() {
+		for key := range values {
+			_ = os.Unsetenv(key)
+		}
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	g := gomega.NewWithT(t)
+	g.Expect(envName("config")).Should(gomega.Equal("CLOUDTEST_CONFIG"))
+	g.Expect(envName("noStop")).Should(gomega.Equal("CLOUDTEST_NO_STOP"))
+	g.Expect(envName("reporting.junit-report")).Should(gomega.Equal("CLOUDTEST_REPORTING_JUNIT_REPORT"))
+}
+
+func TestFlagsFromEnvironment(t *testing.T) {
+	g := gomega.NewWithT(t)
+	defer setEnv(t, map[string]string{
+		"CLOUDTEST_CONFIG":   "env.yaml",
+		"CLOUDTEST_CLUSTERS": "a_provider b_provider",
+		"CLOUDTEST_COUNT":    "5",
+		"CLOUDTEST_NO_STOP":  "true",
+	})()
+
+	rootCmd := &cloudTestCmd{cmdArguments: &Arguments{}}
+	initCmd(rootCmd)
+	g.Expect(rootCmd.ParseFlags([]string{"--count", "2"})).Should(gomega.BeNil())
+	g.Expect(bindEnvironment(&rootCmd.Command)).Should(gomega.BeNil())
+
+	g.Expect(rootCmd.cmdArguments.providerConfig).Should(gomega.Equal("env.yaml"))
+	g.Expect(rootCmd.cmdArguments.clusters).Should(gomega.Equal([]string{"a_provider", "b_provider"}))
+	g.Expect(rootCmd.cmdArguments.count).Should(gomega.Equal(2))
+	g.Expect(rootCmd.cmdArguments.instanceOptions.NoStop).Should(gomega.BeTrue())
+	g.Expect(rootCmd.cmdArguments.instanceOptions.NoInstall).Should(gomega.BeFalse())
+
+	defer setEnv(t, map[string]string{"CLOUDTEST_OUTPUT": "/tmp/step-output"})()
+	g.Expect(bindEnvironment(&rootCmd.Command)).Should(gomega.BeNil())
+	dumpCmd, _, err := rootCmd.Find([]string{"config", "dump"})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(dumpCmd.Flags().Lookup("output").Changed).Should(gomega.BeFalse())
+	g.Expect(dumpCmd.Flags().Lookup("output").Usage).ShouldNot(gomega.ContainSubstring("CLOUDTEST_OUTPUT"))
+
+	defer setEnv(t, map[string]string{"CLOUDTEST_NO_PREPARE": "maybe"})()
+	g.Expect(bindEnvironment(&rootCmd.Command)).ShouldNot(gomega.BeNil())
+}
+
+func TestConfigValuesFromEnvironment(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer utils.ClearFolder(tmpDir, false)
+
+	configFile := path.Join(tmpDir, "cloudtest.yaml")
+	g.Expect(ioutil.WriteFile(configFile, []byte(`---
+timeout: 1h
+reporting:
+  junit-report: "results/junit.xml"
+`), os.ModePerm)).Should(gomega.BeNil())
+
+	defer setEnv(t, map[string]string{
+		"CLOUDTEST_TIMEOUT":                "2h",
+		"CLOUDTEST_REPORTING_JUNIT_REPORT": "env/junit.xml",
+	})()
+	cfg, sources, err := loadEffectiveConfig(&Arguments{
+		providerConfig: configFile,
+		overrides:      []string{"timeout=3h"},
+	})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(cfg.Timeout.Duration()).Should(gomega.Equal(3 * time.Hour))
+	g.Expect(sources["timeout"]).Should(gomega.Equal("--set"))
+	g.Expect(cfg.Reporting.JUnitReportFile).Should(gomega.Equal("env/junit.xml"))
+	g.Expect(sources["reporting.junit-report"]).Should(gomega.Equal("env CLOUDTEST_REPORTING_JUNIT_REPORT"))
+}
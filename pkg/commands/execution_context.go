@@ -6,10 +6,8 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"math/rand"
 	"os"
-	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -18,7 +16,6 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v2"
 
 	"github.com/denis-tingajkin/cloudtest/pkg/config"
 	"github.com/denis-tingajkin/cloudtest/pkg/execmanager"
@@ -34,7 +31,9 @@ import (
 )
 
 const (
-	defaultConfigFile string = ".cloudtest.yaml"
+	defaultConfigFile     string = ".cloudtest.yaml"
+	defaultTestTimeout           = 3 * time.Minute
+	defaultClusterTimeout        = 15 * time.Minute
 )
 
 // Arguments - command line arguments
@@ -43,7 +42,11 @@ type Arguments struct {
 	providerConfig  string   // A folder to start scaning for tests inside
 	count           int      // Limit number of tests to be run per every cloud
 	instanceOptions providers.InstanceOptions
-	onlyEnabled     bool // Disable all clusters and enable only enabled in command line.
+	onlyEnabled     bool     // Disable all clusters and enable only enabled in command line.
+	profile         string   // A configuration profile to apply.
+	overrides       []string // A list of path=value configuration overrides.
+	run             []string // A list of regular expressions, only tests matching any of them are executed.
+	skip            []string // A list of regular expressions, tests matching any of them are skipped.
 }
 
 type clusterState byte
@@ -117,6 +120,8 @@ type operationEvent struct {
 type executionContext struct {
 	sync.RWMutex
 	manager          execmanager.ExecutionManager
+	binaries         *model.TestBinaries
+	buildFailures    map[string][]*model.BuildFailure // Go test packages failed to compile, by execution name
 	clusters         []*clustersGroup
 	operationChannel chan operationEvent
 	tests            []*model.TestEntry
@@ -135,31 +140,9 @@ type executionContext struct {
 
 // CloudTestRun - CloudTestRun
 func CloudTestRun(cmd *cloudTestCmd) {
-	var configFileContent []byte
-	var err error
-
-	if cmd.cmdArguments.providerConfig == "" {
-		cmd.cmdArguments.providerConfig = defaultConfigFile
-	}
-
-	configFileContent, err = ioutil.ReadFile(cmd.cmdArguments.providerConfig)
-	if err != nil {
-		logrus.Errorf("Failed to read config file %v", err)
-		return
-	}
-
-	// Root config
-	testConfig := config.NewCloudTestConfig()
-	err = parseConfig(testConfig, configFileContent)
-	if err != nil {
-		logrus.Errorf("Failed to parse config %v", err)
-		os.Exit(1)
-	}
-
-	// Process config imports
-	err = performImport(testConfig)
+	testConfig, err := loadAndValidateConfig(cmd.cmdArguments)
 	if err != nil {
-		logrus.Errorf("Failed to process config imports %v", err)
+		logrus.Errorf("Failed to load config %v", err)
 		os.Exit(1)
 	}
 
@@ -170,50 +153,9 @@ func CloudTestRun(cmd *cloudTestCmd) {
 	}
 }
 
-func performImport(testConfig *config.CloudTestConfig) error {
-	for _, imp := range testConfig.Imports {
-		if utils.FileExists(imp) {
-			if err := importFiles(testConfig, imp); err != nil {
-				return err
-			}
-			continue
-		}
-		dir, pattern := filepath.Split(imp)
-		files := utils.GetAllFiles(dir)
-		imports, err := utils.FilterByPattern(files, pattern)
-		if err != nil {
-			return err
-		}
-		if err := importFiles(testConfig, imports...); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func importFiles(testConfig *config.CloudTestConfig, files ...string) error {
-	for _, f := range files {
-		importConfig := &config.CloudTestConfig{}
-
-		configFileContent, err := ioutil.ReadFile(f)
-		if err != nil {
-			logrus.Errorf("failed to read config file %v", err)
-			return err
-		}
-		if err = parseConfig(importConfig, configFileContent); err != nil {
-			return err
-		}
-
-		// Do add imported items
-		testConfig.Executions = append(testConfig.Executions, importConfig.Executions...)
-		testConfig.Providers = append(testConfig.Providers, importConfig.Providers...)
-	}
-	return nil
-}
-
 // PerformTesting performs testing uses cloud test config. Returns the junit report when testing finished.
 func PerformTesting(config *config.CloudTestConfig, factory k8s.ValidationFactory, arguments *Arguments) (*reporting.JUnitFile, error) {
+	manager := execmanager.NewExecutionManager(config.ConfigRoot)
 	ctx := &executionContext{
 		cloudTestConfig:  config,
 		operationChannel: make(chan operationEvent, 100),
@@ -223,7 +165,9 @@ func PerformTesting(config *config.CloudTestConfig, factory k8s.ValidationFactor
 		tests:            []*model.TestEntry{},
 		factory:          factory,
 		arguments:        arguments,
-		manager:          execmanager.NewExecutionManager(config.ConfigRoot),
+		manager:          manager,
+		binaries:         model.NewTestBinaries(manager),
+		buildFailures:    map[string][]*model.BuildFailure{},
 	}
 	return performTestingContext(ctx)
 }
@@ -257,17 +201,6 @@ func performTestingContext(ctx *executionContext) (*reporting.JUnitFile, error)
 	return result, err2
 }
 
-func parseConfig(cloudTestConfig *config.CloudTestConfig, configFileContent []byte) error {
-	err := yaml.Unmarshal(configFileContent, cloudTestConfig)
-	if err != nil {
-		err = errors.Wrap(err, "failed to parse configuration file")
-		logrus.Errorf(err.Error())
-		return err
-	}
-	logrus.Infof("configuration file loaded successfully...")
-	return nil
-}
-
 func (ctx *executionContext) performShutdown() {
 	// We need to stop all clusters we started
 	if !ctx.arguments.instanceOptions.NoStop {
@@ -299,7 +232,9 @@ func (ctx *executionContext) performExecution() error {
 	ctx.startTime = time.Now()
 	ctx.clusterReadyTime = ctx.startTime
 
-	timeoutCtx, cancelFunc := context.WithTimeout(context.Background(), time.Duration(ctx.cloudTestConfig.Timeout)*time.Second)
+	ctx.printTimeouts()
+
+	timeoutCtx, cancelFunc := context.WithTimeout(context.Background(), ctx.cloudTestConfig.Timeout.Duration())
 	defer cancelFunc()
 
 	defer func() {
@@ -307,10 +242,7 @@ func (ctx *executionContext) performExecution() error {
 			ctx.printStatistics()
 		}
 	}()
-	statsTimeout := time.Minute
-	if ctx.cloudTestConfig.Statistics.Enabled && ctx.cloudTestConfig.Statistics.Interval > 0 {
-		statsTimeout = time.Duration(ctx.cloudTestConfig.Statistics.Interval) * time.Second
-	}
+	statsTimeout := ctx.getStatisticsInterval()
 	healthCheckChannel := RunHealthChecks(ctx.cloudTestConfig.HealthCheck)
 	termChannel := utils.NewOSSignalChannel()
 	statTicker := time.NewTicker(statsTimeout)
@@ -346,7 +278,7 @@ func (ctx *executionContext) pollEvents(c context.Context, osCh <-chan os.Signal
 	case <-osCh:
 		return errors.New("termination request is received")
 	case <-c.Done():
-		return errors.Errorf("global timeout elapsed: %v seconds", ctx.cloudTestConfig.Timeout)
+		return errors.Errorf("global timeout elapsed: %v", ctx.cloudTestConfig.Timeout)
 	case err := <-healthCh:
 		return errors.Wrapf(err, "health check probe failed")
 	case <-statsCh:
@@ -432,7 +364,8 @@ func (ctx *executionContext) performClusterUpdate(event operationEvent) {
 }
 
 func (ctx *executionContext) processTaskUpdate(event operationEvent) {
-	if event.task.test.Status == model.StatusSuccess || event.task.test.Status == model.StatusFailed {
+	if event.task.test.Status == model.StatusSuccess || event.task.test.Status == model.StatusFailed ||
+		event.task.test.Status == model.StatusSkippedByTest {
 		logrus.Infof("Completed %s on %s, %s, runtime: %v",
 			event.task.test.Name,
 			event.task.clusterTaskID,
@@ -459,7 +392,7 @@ func (ctx *executionContext) processTaskUpdate(event operationEvent) {
 				for _, ci := range event.task.clusterInstances {
 					ids = append(ids, ci.id)
 				}
-				wtime := time.Second * time.Duration(ctx.cloudTestConfig.RetestConfig.WarmupTimeout)
+				wtime := ctx.cloudTestConfig.RetestConfig.WarmupTimeout.Duration()
 				logrus.Infof("Warmup cluster operations: %v timeout: %v", ids, wtime)
 				<-time.After(wtime)
 				// Make cluster as ready
@@ -519,6 +452,8 @@ func statusName(status model.Status) interface{} {
 		return "failed"
 	case model.StatusSkipped:
 		return "skipped"
+	case model.StatusSkippedByTest:
+		return "skipped-by-test"
 	case model.StatusSuccess:
 		return "success"
 	case model.StatusTimeout:
@@ -616,7 +551,7 @@ func (ctx *executionContext) printStatistics() {
 		case model.StatusFailed:
 			failedTests++
 			failedNames += fmt.Sprintf("\n\t\t%s on %s", t.test.Name, t.clusterTaskID)
-		case model.StatusSkippedSinceNoClusters:
+		case model.StatusSkippedSinceNoClusters, model.StatusSkippedByTest:
 			skippedTests++
 		}
 	}
@@ -657,7 +592,9 @@ func fromClusterState(inst *clusterInstance) string {
 
 func (ctx *executionContext) createTasks() {
 	taskIndex := 0
-	for taskOrderIndex, test := range ctx.tests {
+	// Tests filtered out are not counted by --count limit.
+	taskOrderIndex := 0
+	for _, test := range ctx.tests {
 		if test.ExecutionConfig.ConcurrencyRetry > 0 {
 			for j := 0; j < int(test.ExecutionConfig.ConcurrencyRetry); j++ {
 				taskIndex = ctx.createTask(test, taskIndex, taskOrderIndex)
@@ -666,15 +603,18 @@ func (ctx *executionContext) createTasks() {
 		} else {
 			taskIndex = ctx.createTask(test, taskIndex, taskOrderIndex)
 		}
+		if test.Status != model.StatusSkipped {
+			taskOrderIndex++
+		}
 	}
 }
 
 func (ctx *executionContext) createTask(test *model.TestEntry, taskIndex, taskOrderIndex int) int {
-	selector := test.ExecutionConfig.ClusterSelector
+	selector := getClusterSelector(test)
 	// In case of one cluster, we create task copies and execute on every cloud.
 
 	var task *testTask
-	if test.ExecutionConfig.ClusterCount > 1 {
+	if getClusterCount(test) > 1 {
 		for _, clusterName := range selector {
 			for _, cluster := range ctx.clusters {
 				if clusterName == cluster.config.Name {
@@ -701,7 +641,7 @@ func (ctx *executionContext) createTask(test *model.TestEntry, taskIndex, taskOr
 
 	if task == nil {
 		logrus.Errorf("%s: no clusters defined of required %v", test.Name, selector)
-	} else if len(task.clusters) < test.ExecutionConfig.ClusterCount {
+	} else if len(task.clusters) < getClusterCount(test) {
 		logrus.Errorf("%s: not all clusters defined of required %v", test.Name, selector)
 		task.test.Status = model.StatusSkipped
 	} else {
@@ -717,8 +657,17 @@ func (ctx *executionContext) createSingleTask(taskIndex int, test *model.TestEnt
 		test: &model.TestEntry{
 			Kind:            test.Kind,
 			Name:            test.Name,
+			Package:         test.Package,
+			PackageRoot:     test.PackageRoot,
+			PackageDir:      test.PackageDir,
+			Binary:          test.Binary,
 			Tags:            test.Tags,
+			Timeout:         test.Timeout,
+			ClusterCount:    test.ClusterCount,
+			Providers:       test.Providers,
+			RetestCount:     test.RetestCount,
 			Status:          test.Status,
+			SkipMessage:     test.SkipMessage,
 			ExecutionConfig: test.ExecutionConfig,
 			Executions:      []model.TestEntryExecution{},
 			RunScript:       test.RunScript,
@@ -728,19 +677,22 @@ func (ctx *executionContext) createSingleTask(taskIndex int, test *model.TestEnt
 
 	// Generate task key to avoid crossing in cluster tasks map
 	testKey := ""
-	for _, clusterName := range test.ExecutionConfig.ClusterSelector {
+	for _, clusterName := range getClusterSelector(test) {
 		if len(testKey) > 0 {
 			testKey += "_"
 		}
 		testKey += clusterName
 	}
-	task.test.Key = fmt.Sprintf("%s_%s", testKey, test.Name)
+	task.test.Key = fmt.Sprintf("%s_%s_%s", testKey, test.ExecutionConfig.Name, test.QualifiedName())
 
 	// To track cluster task executions.
 	cluster.tasks[task.test.Key] = task
-	if ctx.arguments.count > 0 && taskOrderIndex >= ctx.arguments.count {
+	if test.Status == model.StatusSkipped {
+		logrus.Infof("Skipping test %s: %s", test.Name, test.SkipMessage)
+		ctx.skipped = append(ctx.skipped, task)
+	} else if ctx.arguments.count > 0 && taskOrderIndex >= ctx.arguments.count {
 		logrus.Infof("Limit of tests for execution:: %v is reached. Skipping test %s", ctx.arguments.count, test.Name)
-		test.Status = model.StatusSkipped
+		task.test.Status = model.StatusSkipped
 		ctx.skipped = append(ctx.skipped, task)
 	} else {
 		ctx.tasks = append(ctx.tasks, task)
@@ -798,7 +750,7 @@ func (ctx *executionContext) startTask(task *testTask, instances []*clusterInsta
 	var runner runners.TestRunner
 	switch task.test.Kind {
 	case model.TestEntryKindShellTest:
-		runner = runners.NewShellTestRunner(task.clusterTaskID, task.test)
+		runner = runners.NewShellTestRunner(task.clusterTaskID, task.test, ctx.manager)
 	case model.TestEntryKindGoTest:
 		runner = runners.NewGoTestRunner(task.clusterTaskID, task.test, timeout)
 	default:
@@ -810,7 +762,7 @@ func (ctx *executionContext) startTask(task *testTask, instances []*clusterInsta
 }
 
 func (ctx *executionContext) executeTask(task *testTask, clusterConfigs []string, file io.Writer, runner runners.TestRunner, timeout time.Duration, instances []*clusterInstance, err error, fileName string) {
-	testDelay := func() int {
+	testDelay := func() time.Duration {
 		first := true
 		ctx.RLock()
 		for _, tt := range ctx.completed {
@@ -820,24 +772,28 @@ func (ctx *executionContext) executeTask(task *testTask, clusterConfigs []string
 			}
 		}
 		ctx.RUnlock()
-		delay := 0
+		delay := time.Duration(0)
 		if !first {
 			for _, cl := range task.clusters {
-				if cl.config.TestDelay > delay {
-					delay = cl.config.TestDelay
+				if cl.config.TestDelay.Duration() > delay {
+					delay = cl.config.TestDelay.Duration()
 				}
 			}
 		}
 		return delay
 	}()
 	if testDelay != 0 {
-		logrus.Infof("Cluster %v requires %v seconds delay between tests", task.clusterTaskID, testDelay)
-		<-time.After(time.Duration(testDelay) * time.Second)
-		logrus.Infof("Cluster %v: %v seconds delay between tests completed", task.clusterTaskID, testDelay)
+		logrus.Infof("Cluster %v requires %v delay between tests", task.clusterTaskID, testDelay)
+		<-time.After(testDelay)
+		logrus.Infof("Cluster %v: %v delay between tests completed", task.clusterTaskID, testDelay)
 	}
 
 	st := time.Now()
+	// Outputs of cluster scripts are passed to test as environment variables.
 	var env []string
+	for _, inst := range instances {
+		env = append(env, inst.instance.GetOutputs()...)
+	}
 
 	// Fill Kubernetes environment variables.
 	if len(task.test.ExecutionConfig.KubernetesEnv) > 0 {
@@ -889,7 +845,9 @@ func (ctx *executionContext) executeTask(task *testTask, clusterConfigs []string
 				Name:          "OnFail",
 				ClusterTaskId: task.clusterTaskID,
 				Script:        task.test.ExecutionConfig.OnFail,
-				Env:           append(task.test.ExecutionConfig.Env, fmt.Sprintf("KUBECONFIG=%v", cfg)),
+				Shell:         task.test.ExecutionConfig.Shell,
+				EnvFile:       task.test.ExecutionConfig.EnvFile,
+				Env:           scriptEnv(task.test.ExecutionConfig.Env, task.clusterInstances[i], cfg),
 				Out:           writer,
 			})
 			if onFailErr != nil {
@@ -900,9 +858,10 @@ func (ctx *executionContext) executeTask(task *testTask, clusterConfigs []string
 	}
 
 	// Check if test ask us restart it, and have few executions left
-	if errCode != nil && len(ctx.cloudTestConfig.RetestConfig.Patterns) > 0 && ctx.cloudTestConfig.RetestConfig.RestartCount > 0 {
+	restartCount := ctx.getRestartCount(task.test)
+	if errCode != nil && len(ctx.cloudTestConfig.RetestConfig.Patterns) > 0 && restartCount > 0 {
 		if ctx.matchRestartRequest(fileName) {
-			if len(task.test.Executions) < ctx.cloudTestConfig.RetestConfig.RestartCount {
+			if len(task.test.Executions) < restartCount {
 				// Let's check if we have same cluster instance fail few times one after another with this error.
 				for _, cinst := range task.clusterInstances {
 					cinst.retestCounter++
@@ -921,7 +880,7 @@ func (ctx *executionContext) executeTask(task *testTask, clusterConfigs []string
 
 				ctx.updateTestExecution(task, fileName, model.StatusRerunRequest)
 			} else {
-				msg := fmt.Sprintf("Test %v retry count %v exceed: err: %v", task.test.Name, ctx.cloudTestConfig.RetestConfig.RestartCount, errCode.Error())
+				msg := fmt.Sprintf("Test %v retry count %v exceed: err: %v", task.test.Name, restartCount, errCode.Error())
 				logrus.Errorf(msg)
 				_, _ = writer.WriteString(errCode.Error())
 				_ = writer.Flush()
@@ -971,6 +930,8 @@ func (ctx *executionContext) executeTask(task *testTask, clusterConfigs []string
 			_ = writer.Flush()
 			ctx.updateTestExecution(task, fileName, model.StatusFailed)
 		}
+	} else if result := task.test.Result(); result != nil && result.Status == model.StatusSkippedByTest {
+		ctx.updateTestExecution(task, fileName, model.StatusSkippedByTest)
 	} else {
 		ctx.updateTestExecution(task, fileName, model.StatusSuccess)
 	}
@@ -987,7 +948,9 @@ func (ctx *executionContext) handleBeforeAfterScripts(task *testTask, writer *bu
 					Name:          "After",
 					ClusterTaskId: task.clusterTaskID,
 					Script:        inst.runningExecution.After,
-					Env:           append(inst.runningExecution.Env, fmt.Sprintf("KUBECONFIG=%v", cfg)),
+					Shell:         inst.runningExecution.Shell,
+					EnvFile:       inst.runningExecution.EnvFile,
+					Env:           scriptEnv(inst.runningExecution.Env, inst, cfg),
 					Out:           writer,
 				})
 				if err != nil {
@@ -1001,7 +964,9 @@ func (ctx *executionContext) handleBeforeAfterScripts(task *testTask, writer *bu
 				Name:          "Before",
 				ClusterTaskId: task.clusterTaskID,
 				Script:        task.test.ExecutionConfig.Before,
-				Env:           append(task.test.ExecutionConfig.Env, fmt.Sprintf("KUBECONFIG=%v", cfg)),
+				Shell:         task.test.ExecutionConfig.Shell,
+				EnvFile:       task.test.ExecutionConfig.EnvFile,
+				Env:           scriptEnv(task.test.ExecutionConfig.Env, inst, cfg),
 				Out:           writer,
 			})
 			if err != nil {
@@ -1012,6 +977,14 @@ func (ctx *executionContext) handleBeforeAfterScripts(task *testTask, writer *bu
 	}
 }
 
+// scriptEnv - return environment of execution script running against cluster instance, outputs of cluster scripts
+// are added to execution environment.
+func scriptEnv(env []string, inst *clusterInstance, clusterConfig string) []string {
+	result := append([]string{}, env...)
+	result = append(result, inst.instance.GetOutputs()...)
+	return append(result, fmt.Sprintf("KUBECONFIG=%v", clusterConfig))
+}
+
 func (ctx *executionContext) matchRestartRequest(fileName string) bool {
 	// Check if output file contains restart request marker
 	f, err := os.OpenFile(fileName, os.O_RDONLY, 0600)
@@ -1034,12 +1007,71 @@ func (ctx *executionContext) matchRestartRequest(fileName string) bool {
 }
 
 func (ctx *executionContext) getTestTimeout(task *testTask) time.Duration {
-	timeout := time.Second * time.Duration(task.test.ExecutionConfig.Timeout) * 2
-	if timeout == 0 {
-		logrus.Infof("test timeout is not specified, use default value, 3min")
-		timeout = time.Minute * 3
+	if task.test.Timeout > 0 {
+		return task.test.Timeout
 	}
-	return timeout
+	return getExecutionTimeout(task.test.ExecutionConfig)
+}
+
+// getClusterCount - return a number of clusters required for test.
+func getClusterCount(test *model.TestEntry) int {
+	if test.ClusterCount > 0 {
+		return test.ClusterCount
+	}
+	return test.ExecutionConfig.ClusterCount
+}
+
+// getClusterSelector - return names of cluster providers test should be executed on.
+func getClusterSelector(test *model.TestEntry) []string {
+	if len(test.Providers) > 0 {
+		return test.Providers
+	}
+	return test.ExecutionConfig.ClusterSelector
+}
+
+// getRestartCount - return a number of test restarts allowed on retest request.
+func (ctx *executionContext) getRestartCount(test *model.TestEntry) int {
+	if test.RetestCount > 0 {
+		return test.RetestCount
+	}
+	return ctx.cloudTestConfig.RetestConfig.RestartCount
+}
+
+func getExecutionTimeout(execution *config.Execution) time.Duration {
+	if execution.Timeout == 0 {
+		return defaultTestTimeout
+	}
+	return execution.Timeout.Duration()
+}
+
+func (ctx *executionContext) getStatisticsInterval() time.Duration {
+	if ctx.cloudTestConfig.Statistics.Enabled && ctx.cloudTestConfig.Statistics.Interval > 0 {
+		return ctx.cloudTestConfig.Statistics.Interval.Duration()
+	}
+	return time.Minute
+}
+
+// printTimeouts - print effective timeout values will be used during execution.
+func (ctx *executionContext) printTimeouts() {
+	msg := strings.Builder{}
+	_, _ = msg.WriteString(fmt.Sprintf("Timeouts:\n\tGlobal timeout: %v", ctx.cloudTestConfig.Timeout))
+	if ctx.cloudTestConfig.Statistics.Enabled {
+		_, _ = msg.WriteString(fmt.Sprintf("\n\tStatistics interval: %v", ctx.getStatisticsInterval()))
+	}
+	if ctx.cloudTestConfig.RetestConfig.WarmupTimeout > 0 {
+		_, _ = msg.WriteString(fmt.Sprintf("\n\tRetest warmup time: %v", ctx.cloudTestConfig.RetestConfig.WarmupTimeout))
+	}
+	for _, hc := range ctx.cloudTestConfig.HealthCheck {
+		_, _ = msg.WriteString(fmt.Sprintf("\n\tHealth check %q interval: %v", hc.Message, hc.Interval))
+	}
+	for _, cl := range ctx.clusters {
+		_, _ = msg.WriteString(fmt.Sprintf("\n\tCluster %v: timeout: %v, stop delay: %v, test delay: %v",
+			cl.config.Name, ctx.getClusterTimeout(cl), cl.config.StopDelay, cl.config.TestDelay))
+	}
+	for _, exec := range ctx.cloudTestConfig.Executions {
+		_, _ = msg.WriteString(fmt.Sprintf("\n\tExecution %v: test timeout: %v", exec.Name, getExecutionTimeout(exec)))
+	}
+	logrus.Info(msg.String())
 }
 
 func (ctx *executionContext) updateTestExecution(task *testTask, fileName string, status model.Status) {
@@ -1113,12 +1145,10 @@ func (ctx *executionContext) startCluster(ci *clusterInstance) bool {
 }
 
 func (ctx *executionContext) getClusterTimeout(group *clustersGroup) time.Duration {
-	timeout := time.Duration(group.config.Timeout) * time.Second
 	if group.config.Timeout == 0 {
-		logrus.Infof("cluster timeout is not specified, use default value 15min")
-		timeout = 15 * time.Minute
+		return defaultClusterTimeout
 	}
-	return timeout
+	return group.config.Timeout.Duration()
 }
 
 func (ctx *executionContext) monitorCluster(context context.Context, ci *clusterInstance) {
@@ -1184,7 +1214,7 @@ func (ctx *executionContext) destroyCluster(ci *clusterInstance, sendUpdate, for
 
 	if ci.group.config.StopDelay != 0 {
 		logrus.Infof("Cluster stop warm-up timeout specified %v", ci.group.config.StopDelay)
-		<-time.After(time.Duration(ci.group.config.StopDelay) * time.Second)
+		<-time.After(ci.group.config.StopDelay.Duration())
 	}
 	ctx.Lock()
 	ci.state = clusterCrashed
@@ -1206,18 +1236,13 @@ func (ctx *executionContext) createClusters() error {
 	}
 
 	for _, cl := range ctx.cloudTestConfig.Providers {
-		if ctx.arguments.onlyEnabled {
+		enabled := isProviderEnabled(cl, ctx.arguments)
+		if cl.Enabled && !enabled {
 			logrus.Infof("Disable cluster config:: %v since onlyEnabled is passed...", cl.Name)
-			cl.Enabled = false
-		}
-		for _, cc := range ctx.arguments.clusters {
-			if cl.Name == cc {
-				if !cl.Enabled {
-					logrus.Infof("Enabling config:: %v", cl.Name)
-				}
-				cl.Enabled = true
-			}
+		} else if !cl.Enabled && enabled {
+			logrus.Infof("Enabling config:: %v", cl.Name)
 		}
+		cl.Enabled = enabled
 		if cl.Enabled {
 			logrus.Infof("Initialize provider for config:: %v %v", cl.Name, cl.Kind)
 			provider, ok := clusterProviders[cl.Kind]
@@ -1291,28 +1316,50 @@ func (ctx *executionContext) findTests() error {
 		if exec.Name == "" {
 			return errors.New("execution name should be specified")
 		}
+		var tests []*model.TestEntry
 		if exec.Kind == "" || exec.Kind == "gotest" {
-			tests, err := ctx.findGoTest(exec)
-			if err != nil {
+			var err error
+			if tests, err = ctx.findGoTest(exec); err != nil {
 				return err
 			}
-			ctx.appendTests(tests...)
 		} else if exec.Kind == "shell" {
-			tests := ctx.findShellTest(exec)
-			ctx.appendTests(tests...)
+			var err error
+			if tests, err = ctx.findShellTest(exec); err != nil {
+				return err
+			}
 		} else {
 			return errors.Errorf("unknown executon kind %v", exec.Kind)
 		}
+		// Filtered out tests are kept to be reported as skipped.
+		filters, err := newTestFilters(&exec.Source, ctx.arguments)
+		if err != nil {
+			return errors.Wrapf(err, "execution %s", exec.Name)
+		}
+		filterTests(tests, filters)
+		ctx.appendTests(tests...)
 	}
 	// If we have execution without tags, we need to remove all tests from it from tagged executions.
 	logrus.Infof("Total tests found: %v", len(ctx.tests))
-	if len(ctx.tests) == 0 {
+	if len(ctx.tests) == 0 && len(ctx.buildFailures) == 0 {
 		return errors.New("there is no tests defined")
 	}
 	return nil
 }
 
-func (ctx *executionContext) findShellTest(exec *config.Execution) []*model.TestEntry {
+func (ctx *executionContext) findShellTest(exec *config.Execution) ([]*model.TestEntry, error) {
+	if len(exec.Scripts) > 0 {
+		tests, err := model.GetShellTests(exec.Scripts, exec.Source)
+		if err != nil {
+			logrus.Errorf("Failed during shell test lookup %v", err)
+			return nil, err
+		}
+		logrus.Infof("Shell tests found: %v", len(tests))
+		for _, t := range tests {
+			t.ExecutionConfig = exec
+			t.Status = model.StatusAdded
+		}
+		return tests, nil
+	}
 	return []*model.TestEntry{
 		{
 			Name:            exec.Name,
@@ -1322,17 +1369,26 @@ func (ctx *executionContext) findShellTest(exec *config.Execution) []*model.Test
 			Status:          model.StatusAdded,
 			RunScript:       exec.Run,
 		},
-	}
+	}, nil
 }
 
 func (ctx *executionContext) findGoTest(executionConfig *config.Execution) ([]*model.TestEntry, error) {
 	st := time.Now()
 	logrus.Infof("Starting finding tests by source %v", executionConfig.Source)
-	execTests, err := model.GetTestConfiguration(ctx.manager, executionConfig.PackageRoot, executionConfig.Source)
+	execTests, failures, err := model.GetTestConfiguration(ctx.binaries, executionConfig.PackageRoot, executionConfig.Source)
 	if err != nil {
 		logrus.Errorf("Failed during test lookup %v", err)
 		return nil, err
 	}
+	if len(failures) > 0 {
+		ctx.buildFailures[executionConfig.Name] = failures
+	}
+	if executionConfig.ExpandSubtests {
+		if execTests, err = model.ExpandSubtests(ctx.manager, execTests); err != nil {
+			logrus.Errorf("Failed during subtest lookup %v", err)
+			return nil, err
+		}
+	}
 	logrus.Infof("Tests found: %v Elapsed: %v", len(execTests), time.Since(st))
 	var result []*model.TestEntry
 	for _, t := range execTests {
@@ -1341,7 +1397,7 @@ func (ctx *executionContext) findGoTest(executionConfig *config.Execution) ([]*m
 		match := true
 		for _, v := range executionConfig.OnlyRun {
 			match = false
-			if v == t.Name {
+			if t.Matches(v) {
 				match = true
 				break
 			}
@@ -1371,6 +1427,11 @@ func (ctx *executionContext) generateJUnitReportFile() (*reporting.JUnitFile, er
 
 	// We need to group all tests by executions.
 	executionsTests := ctx.getAllTestTasksGroupedByExecutions()
+	for execName := range ctx.buildFailures {
+		if _, ok := executionsTests[execName]; !ok {
+			executionsTests[execName] = nil
+		}
+	}
 
 	totalFailures := 0
 	totalTests := 0
@@ -1388,8 +1449,9 @@ func (ctx *executionContext) generateJUnitReportFile() (*reporting.JUnitFile, er
 			clustersTests[clusterGroupName] = append(clustersTests[clusterGroupName], test)
 		}
 
-		executionFailures := 0
-		executionTests := 0
+		// Packages failed to compile are reported once for execution.
+		executionFailures := ctx.generateBuildFailuresReport(execName, execSuite)
+		executionTests := executionFailures
 		executionTime := time.Duration(0)
 
 		// Generate nested suites by cluster types.
@@ -1447,6 +1509,22 @@ func (ctx *executionContext) generateJUnitReportFile() (*reporting.JUnitFile, er
 	return ctx.report, nil
 }
 
+func (ctx *executionContext) generateBuildFailuresReport(execName string, suite *reporting.Suite) int {
+	for _, failure := range ctx.buildFailures[execName] {
+		suite.TestCases = append(suite.TestCases, &reporting.TestCase{
+			Classname: failure.Package,
+			Name:      "Build-" + failure.Package,
+			Time:      "0",
+			Failure: &reporting.Failure{
+				Type:     "ERROR",
+				Message:  fmt.Sprintf("Failed to compile tests of %s", failure.Package),
+				Contents: failure.Output,
+			},
+		})
+	}
+	return len(ctx.buildFailures[execName])
+}
+
 func (ctx *executionContext) generateClusterFailuresReportSuite() (time.Duration, int, *reporting.Suite) {
 	clusterFailuresSuite := &reporting.Suite{
 		Name: "Cluster failures",
@@ -1533,9 +1611,16 @@ func (ctx *executionContext) generateClusterFailedReportEntry(inst *clusterInsta
 
 func (ctx *executionContext) generateTestCaseReport(test *testTask, totalTests int, totalTime time.Duration, failures int, suite *reporting.Suite) (int, time.Duration, int) {
 	testCase := &reporting.TestCase{
-		Name:    test.test.Name,
-		Time:    fmt.Sprintf("%v", test.test.Duration.Seconds()),
-		Cluster: test.clusterTaskID,
+		Classname: test.test.Package,
+		Name:      test.test.Name,
+		Time:      fmt.Sprintf("%v", test.test.Duration.Seconds()),
+		Cluster:   test.clusterTaskID,
+	}
+	if test.test.Tags != "" {
+		testCase.Properties = append(testCase.Properties, &reporting.Property{
+			Name:  "tags",
+			Value: test.test.Tags,
+		})
 	}
 
 	switch test.test.Status {
@@ -1563,6 +1648,14 @@ func (ctx *executionContext) generateTestCaseReport(test *testTask, totalTests i
 			msg = test.test.SkipMessage
 		}
 
+		testCase.SkipMessage = &reporting.SkipMessage{
+			Message: msg,
+		}
+	case model.StatusSkippedByTest:
+		msg := "Skipped by test"
+		if result := test.test.Result(); result != nil && result.SkipReason() != "" {
+			msg = result.SkipReason()
+		}
 		testCase.SkipMessage = &reporting.SkipMessage{
 			Message: msg,
 		}
@@ -1581,11 +1674,40 @@ func (ctx *executionContext) generateTestCaseReport(test *testTask, totalTests i
 			failures++
 		}
 	}
+	testCase.TestCases = generateSubtestCaseReports(test.test, test.test.Name, test.clusterTaskID)
 	suite.TestCases = append(suite.TestCases, testCase)
 
 	return totalTests + 1, totalTime + test.test.Duration, failures
 }
 
+// generateSubtestCaseReports - generate nested testcases of subtests of test name reported by last execution.
+func generateSubtestCaseReports(test *model.TestEntry, name, clusterTaskID string) []*reporting.TestCase {
+	var result []*reporting.TestCase
+	for _, subtest := range test.SubtestResults(name) {
+		testCase := &reporting.TestCase{
+			Classname: test.Package,
+			Name:      subtest.Name,
+			Time:      fmt.Sprintf("%v", subtest.Duration.Seconds()),
+			Cluster:   clusterTaskID,
+		}
+		switch subtest.Status {
+		case model.StatusFailed:
+			testCase.Failure = &reporting.Failure{
+				Type:     "ERROR",
+				Contents: subtest.Output,
+				Message:  fmt.Sprintf("Test execution failed %v", subtest.Name),
+			}
+		case model.StatusSkippedByTest:
+			testCase.SkipMessage = &reporting.SkipMessage{
+				Message: subtest.SkipReason(),
+			}
+		}
+		testCase.TestCases = generateSubtestCaseReports(test, subtest.Name, clusterTaskID)
+		result = append(result, testCase)
+	}
+	return result
+}
+
 func hasFailedCluster(task *testTask) bool {
 	for _, cg := range task.clusters {
 		failedInstances := 0
@@ -1630,27 +1752,49 @@ func (ctx *executionContext) isClusterDown(inst *clusterInstance) bool {
 }
 
 func (ctx *executionContext) handleScript(args *runScriptArgs) error {
-	if strings.TrimSpace(args.Script) == "" {
+	if strings.TrimSpace(args.Script.String()) == "" {
 		logrus.Warnf("%v is empty script. Nothing to run", args.Name)
 		return nil
 	}
 	mgr := shell_mgr.NewEnvironmentManager()
-	if err := mgr.ProcessEnvironment(args.ClusterTaskId, "shellrun", os.TempDir(), args.Env, map[string]string{"test-name": args.Name}); err != nil {
+	scriptArgs := map[string]string{"test-name": args.Name}
+	if err := mgr.ProcessEnvironment(args.ClusterTaskId, "shellrun", os.TempDir(), args.EnvFile, args.Env, scriptArgs); err != nil {
 		logrus.Errorf("%sv: an error during process env: %v", args.Name, err)
 		return err
 	}
-	context, cancel := context.WithTimeout(context.Background(), runScriptTimeout)
-	defer cancel()
-	return runScript(context, args.Name, args.Script, mgr.GetProcessedEnv(), args.Out)
+	return shell_mgr.RunSteps(context.Background(), args.Script, &shell_mgr.StepOptions{
+		Name:    args.Name,
+		Timeout: runScriptTimeout,
+		Env:     mgr.GetProcessedEnv(),
+		Args:    scriptArgs,
+		Out:     args.Out,
+		OpenLog: func(step string) (io.WriteCloser, error) {
+			// Output of plain script is written to test output only, steps have their own log files.
+			if step == "" {
+				return nil, nil
+			}
+			_, file, err := ctx.manager.OpenFile(args.ClusterTaskId, args.Name+"-"+step)
+			return file, err
+		},
+	}, func(stepCtx context.Context, script string, env []string, writer *bufio.Writer) error {
+		return runScript(stepCtx, args.Name, script, args.Shell, env, writer)
+	})
 }
 
-func runScript(ctx context.Context, name, script string, env []string, writer *bufio.Writer) error {
+func runScript(ctx context.Context, name, script, shell string, env []string, writer *bufio.Writer) error {
 	logger := func(s string) {
 	}
 	root, err := os.Getwd()
 	if err != nil {
 		return err
 	}
+	if shell == utils.ShellBash {
+		if _, err := utils.RunScript(ctx, script, root, logger, writer, env, nil, false); err != nil {
+			logrus.Errorf("An error during run script: %v, err: %v", name, err.Error())
+			return errors.WithMessage(err, fmt.Sprintf("Error(s) from '%v' script", name))
+		}
+		return nil
+	}
 	var errs []string
 	for _, cmd := range utils.ParseScript(script) {
 		_, err := utils.RunCommand(ctx, cmd, root, logger, writer, env, nil, false)
@@ -1705,7 +1849,8 @@ func ExecuteCloudTest() {
 	}
 	rootCmd.Use = "cloud_test"
 	rootCmd.Short = "NSM Cloud Test is cloud helper continuous integration testing tool"
-	rootCmd.Long = `Allow to execute all set of individual tests across all clouds provided.`
+	rootCmd.Long = `Allow to execute all set of individual tests across all clouds provided.
+` + envHelp()
 	rootCmd.Run = func(cmd *cobra.Command, args []string) {
 		CloudTestRun(rootCmd)
 	}
@@ -1722,16 +1867,22 @@ func ExecuteCloudTest() {
 }
 
 func initCmd(rootCmd *cloudTestCmd) {
-	cobra.OnInitialize(initConfig)
-	rootCmd.Flags().StringVarP(&rootCmd.cmdArguments.providerConfig, "config", "", "", "Config file for providers, default="+defaultConfigFile)
-	rootCmd.Flags().StringArrayVarP(&rootCmd.cmdArguments.clusters, "clusters", "c", []string{}, "Enable disable cluster configs, default use from config. Cloud be used to test against selected configuration or locally...")
-	rootCmd.Flags().BoolVarP(&rootCmd.cmdArguments.onlyEnabled, "enabled", "e", false, "Use only passed cluster names...")
-	rootCmd.Flags().IntVarP(&rootCmd.cmdArguments.count, "count", "", -1, "Execute only count of tests")
+	cobra.OnInitialize(func() {
+		initConfig(rootCmd)
+	})
+	rootCmd.PersistentFlags().StringVarP(&rootCmd.cmdArguments.providerConfig, "config", "", "", "Config file for providers, default="+defaultConfigFile)
+	rootCmd.PersistentFlags().StringArrayVarP(&rootCmd.cmdArguments.clusters, "clusters", "c", []string{}, "Enable disable cluster configs, default use from config. Cloud be used to test against selected configuration or locally...")
+	rootCmd.PersistentFlags().BoolVarP(&rootCmd.cmdArguments.onlyEnabled, "enabled", "e", false, "Use only passed cluster names...")
+	rootCmd.PersistentFlags().StringVarP(&rootCmd.cmdArguments.profile, "profile", "", "", "Apply named configuration profile after imports are processed")
+	rootCmd.PersistentFlags().StringArrayVarP(&rootCmd.cmdArguments.overrides, "set", "", []string{}, "Override configuration value after profile is applied, like executions.basic.timeout=600 or providers.packet.instances=4")
+	rootCmd.PersistentFlags().IntVarP(&rootCmd.cmdArguments.count, "count", "", -1, "Execute only count of tests")
+	rootCmd.PersistentFlags().StringArrayVarP(&rootCmd.cmdArguments.run, "run", "", []string{}, "Execute only tests matching regular expression, of all executions")
+	rootCmd.PersistentFlags().StringArrayVarP(&rootCmd.cmdArguments.skip, "skip", "", []string{}, "Skip tests matching regular expression, of all executions")
 
 	rootCmd.Flags().BoolVarP(&rootCmd.cmdArguments.instanceOptions.NoStop, "noStop", "", false, "Pass to disable stop operations...")
 	rootCmd.Flags().BoolVarP(&rootCmd.cmdArguments.instanceOptions.NoInstall, "noInstall", "", false, "Pass to disable do install operations...")
 	rootCmd.Flags().BoolVarP(&rootCmd.cmdArguments.instanceOptions.NoPrepare, "noPrepare", "", false, "Pass to disable do prepare operations...")
-	rootCmd.Flags().BoolVarP(&rootCmd.cmdArguments.instanceOptions.NoMaskParameters, "noMask", "", false, "Pass to disable masking of environment variables...")
+	rootCmd.PersistentFlags().BoolVarP(&rootCmd.cmdArguments.instanceOptions.NoMaskParameters, "noMask", "", false, "Pass to disable masking of environment variables...")
 
 	var versionCmd = &cobra.Command{
 		Use:   "version",
@@ -1742,7 +1893,16 @@ func initCmd(rootCmd *cloudTestCmd) {
 		},
 	}
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(newValidateCmd(rootCmd))
+	rootCmd.AddCommand(newConfigCmd(rootCmd))
+	rootCmd.AddCommand(newSchemaCmd())
+	documentEnvironment(&rootCmd.Command)
 }
 
-func initConfig() {
+// initConfig - apply CLOUDTEST_* environment variables to flags not passed in command line.
+func initConfig(rootCmd *cloudTestCmd) {
+	if err := bindEnvironment(&rootCmd.Command); err != nil {
+		logrus.Errorf("%v", err)
+		os.Exit(1)
+	}
 }
@@ -37,7 +37,7 @@ func RunHealthChecks(checkConfigs []*config.HealthCheckConfig) <-chan error {
 		go func(c int) {
 			config := checkConfigs[c]
 			for {
-				interval := time.Duration(config.Interval) * time.Second
+				interval := config.Interval.Duration()
 				<-time.After(interval)
 
 				timeoutCtx, cancel := context.WithTimeout(context.Background(), interval)
@@ -18,9 +18,13 @@ package commands
 
 import (
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
 
 	"github.com/onsi/gomega"
 )
@@ -30,8 +34,10 @@ func TestImportAll(t *testing.T) {
 	testConfig := &config.CloudTestConfig{
 		Imports: []string{"samples/.*"},
 	}
-	err := performImport(testConfig)
+	loader := newConfigLoader()
+	err := loader.performImport(testConfig, map[string]bool{}, "")
 	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(loader.problems).Should(gomega.BeEmpty())
 	files, _ := ioutil.ReadDir(testConfig.Imports[0][:len(testConfig.Imports[0])-1])
 	g.Expect(len(testConfig.Executions) == len(files)).Should(gomega.BeTrue())
 }
@@ -41,8 +47,10 @@ func TestImportPattern(t *testing.T) {
 	testConfig := &config.CloudTestConfig{
 		Imports: []string{"samples/.*2"},
 	}
-	err := performImport(testConfig)
+	loader := newConfigLoader()
+	err := loader.performImport(testConfig, map[string]bool{}, "")
 	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(loader.problems).Should(gomega.BeEmpty())
 	g.Expect(len(testConfig.Executions)).Should(gomega.Equal(1))
 }
 
@@ -51,7 +59,130 @@ func TestSpecificImport(t *testing.T) {
 	testConfig := &config.CloudTestConfig{
 		Imports: []string{"samples/execution1.yaml"},
 	}
-	err := performImport(testConfig)
+	loader := newConfigLoader()
+	err := loader.performImport(testConfig, map[string]bool{}, "")
 	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(loader.problems).Should(gomega.BeEmpty())
 	g.Expect(len(testConfig.Executions)).Should(gomega.Equal(1))
 }
+
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	for name, content := range files {
+		g.Expect(os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), os.ModePerm)).Should(gomega.BeNil())
+		g.Expect(ioutil.WriteFile(filepath.Join(tmpDir, name), []byte(content), os.ModePerm)).Should(gomega.BeNil())
+	}
+	return tmpDir
+}
+
+func TestNestedRelativeImports(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"cloudtest.yaml": `---
+import:
+  - conf/providers.yaml
+  - conf/override.yaml
+executions:
+  - name: "root"
+reporting:
+  junit-report: "root.xml"
+`,
+		"conf/providers.yaml": `---
+import:
+  - executions/.*\.yaml
+providers:
+  - name: "a_provider"
+reporting:
+  junit-report: "providers.xml"
+retest:
+  count: 1
+statistics:
+  enabled: false
+health-check:
+  - run: "echo providers"
+`,
+		"conf/override.yaml": `---
+retest:
+  count: 5
+health-check:
+  - run: "echo override"
+`,
+		"conf/executions/first.yaml": `---
+executions:
+  - name: "first"
+`,
+		"conf/executions/second.yaml": `---
+executions:
+  - name: "second"
+`,
+	})
+	defer utils.ClearFolder(tmpDir, false)
+
+	cfg, _, err := loadConfig(filepath.Join(tmpDir, "cloudtest.yaml"))
+	g.Expect(err).Should(gomega.BeNil())
+
+	var names []string
+	for _, exec := range cfg.Executions {
+		names = append(names, exec.Name)
+	}
+	g.Expect(names).Should(gomega.Equal([]string{"root", "first", "second"}))
+	g.Expect(len(cfg.Providers)).Should(gomega.Equal(1))
+	g.Expect(len(cfg.HealthCheck)).Should(gomega.Equal(2))
+	g.Expect(cfg.Reporting.JUnitReportFile).Should(gomega.Equal("root.xml"))
+	g.Expect(cfg.RetestConfig.RestartCount).Should(gomega.Equal(5))
+	g.Expect(cfg.Statistics.Enabled).Should(gomega.BeFalse())
+	g.Expect(cfg.Imports).Should(gomega.Equal([]string{"conf/providers.yaml", "conf/override.yaml"}))
+}
+
+func TestImportCycle(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"a.yaml": "import:\n  - b.yaml\n",
+		"b.yaml": "import:\n  - a.yaml\n",
+	})
+	defer utils.ClearFolder(tmpDir, false)
+
+	_, _, err := loadConfig(filepath.Join(tmpDir, "a.yaml"))
+	g.Expect(err).ShouldNot(gomega.BeNil())
+	g.Expect(err.Error()).Should(gomega.ContainSubstring("import cycle detected: " +
+		filepath.Join(tmpDir, "a.yaml") + " -> " + filepath.Join(tmpDir, "b.yaml") + " -> " + filepath.Join(tmpDir, "a.yaml")))
+}
+
+func TestImportDuplicateNames(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"a.yaml": "import:\n  - b.yaml\nproviders:\n  - name: p\nexecutions:\n  - name: e\n",
+		"b.yaml": "providers:\n  - name: p\nexecutions:\n  - name: e\n",
+	})
+	defer utils.ClearFolder(tmpDir, false)
+
+	a, b := filepath.Join(tmpDir, "a.yaml"), filepath.Join(tmpDir, "b.yaml")
+	_, _, err := loadConfig(a)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+	g.Expect(err.(configErrors)).Should(gomega.ConsistOf(
+		`provider "p" is defined in both `+a+" and "+b,
+		`execution "e" is defined in both `+a+" and "+b,
+	))
+}
+
+func TestImportKeepsDefaults(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"cloudtest.yaml": `---
+import:
+  - statistics.yaml
+`,
+		"statistics.yaml": `---
+statistics:
+  interval: 30
+`,
+	})
+	defer utils.ClearFolder(tmpDir, false)
+
+	cfg, _, err := loadConfig(filepath.Join(tmpDir, "cloudtest.yaml"))
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(cfg.Statistics.Enabled).Should(gomega.BeTrue())
+	g.Expect(cfg.Statistics.Interval.Duration()).Should(gomega.Equal(30 * time.Second))
+}
@@ -46,12 +46,12 @@ func TestUpdateTaskWithTimeout_ShouldNotCompleteTask(t *testing.T) {
 		running:          make(map[string]*testTask),
 		operationChannel: make(chan operationEvent, 1),
 	}
-	ctx.cloudTestConfig.Timeout = 2
+	ctx.cloudTestConfig.Timeout = config.Duration(2 * time.Second)
 	ctx.cloudTestConfig.Statistics.Enabled = false
 	task := &testTask{
 		test: &model.TestEntry{
 			ExecutionConfig: &config.Execution{
-				Timeout: 1,
+				Timeout: config.Duration(time.Second),
 			},
 			Status: model.StatusSkipped,
 		},
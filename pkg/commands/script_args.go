@@ -19,12 +19,17 @@ package commands
 import (
 	"bufio"
 	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
 )
 
+// runScriptTimeout - a timeout of every step of before, after and on fail scripts without own timeout.
 const runScriptTimeout = time.Minute * 3
 
 type runScriptArgs struct {
-	Name, ClusterTaskId, Script string
-	Env                         []string
-	Out                         *bufio.Writer
+	Name, ClusterTaskId, Shell string
+	Script                     config.Script
+	EnvFile                    config.EnvFiles
+	Env                        []string
+	Out                        *bufio.Writer
 }
@@ -0,0 +1,111 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/model"
+)
+
+// testFilter - a list of regular expressions tests are included or excluded by, expressions are matched against test
+// name and qualified name.
+type testFilter struct {
+	name     string // A name of filter used in skip reason, like --run or source.exclude
+	exclude  bool
+	patterns []*regexp.Regexp
+}
+
+func newTestFilter(name string, exclude bool, patterns []string) (*testFilter, error) {
+	filter := &testFilter{name: name, exclude: exclude}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s expression %q", name, p)
+		}
+		filter.patterns = append(filter.patterns, re)
+	}
+	return filter, nil
+}
+
+// newTestFilters - return filters of execution source followed by filters of --run and --skip flags.
+func newTestFilters(source *config.ExecutionSource, arguments *Arguments) ([]*testFilter, error) {
+	var result []*testFilter
+	for _, f := range []struct {
+		name     string
+		exclude  bool
+		patterns []string
+	}{
+		{"source.include", false, source.Include},
+		{"source.exclude", true, source.Exclude},
+		{"--run", false, arguments.run},
+		{"--skip", true, arguments.skip},
+	} {
+		if len(f.patterns) == 0 {
+			continue
+		}
+		filter, err := newTestFilter(f.name, f.exclude, f.patterns)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, filter)
+	}
+	return result, nil
+}
+
+func (f *testFilter) match(test *model.TestEntry) *regexp.Regexp {
+	for _, re := range f.patterns {
+		if re.MatchString(test.Name) || re.MatchString(test.QualifiedName()) {
+			return re
+		}
+	}
+	return nil
+}
+
+// skipReason - return a reason test is filtered out by, empty if test passes filter.
+func (f *testFilter) skipReason(test *model.TestEntry) string {
+	re := f.match(test)
+	if f.exclude && re != nil {
+		return fmt.Sprintf("Excluded by %s %q", f.name, re.String())
+	}
+	if !f.exclude && re == nil {
+		var patterns []string
+		for _, p := range f.patterns {
+			patterns = append(patterns, fmt.Sprintf("%q", p.String()))
+		}
+		return fmt.Sprintf("Not included by %s %s", f.name, strings.Join(patterns, ", "))
+	}
+	return ""
+}
+
+// filterTests - mark tests filtered out by any of filters as skipped with a filter as skip reason.
+func filterTests(tests []*model.TestEntry, filters []*testFilter) {
+	for _, test := range tests {
+		for _, f := range filters {
+			if reason := f.skipReason(test); reason != "" {
+				test.Status = model.StatusSkipped
+				test.SkipMessage = reason
+				break
+			}
+		}
+	}
+}
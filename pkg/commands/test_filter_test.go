@@ -0,0 +1,91 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/model"
+)
+
+func TestFilterTests(t *testing.T) {
+	g := gomega.NewWithT(t)
+	source := &config.ExecutionSource{
+		Include: []string{"^Test[AB]$", "other/pkg"},
+		Exclude: []string{"C$"},
+	}
+	filters, err := newTestFilters(source, &Arguments{skip: []string{"B"}})
+	g.Expect(err).Should(gomega.BeNil())
+
+	tests := []*model.TestEntry{
+		{Name: "TestA"},
+		{Name: "TestB"},
+		{Name: "TestC", Package: "github.com/other/pkg"},
+		{Name: "TestD", Package: "github.com/other/pkg"},
+		{Name: "TestE"},
+	}
+	filterTests(tests, filters)
+	var statuses, messages []string
+	for _, test := range tests {
+		statuses = append(statuses, statusName(test.Status).(string))
+		messages = append(messages, test.SkipMessage)
+	}
+	g.Expect(statuses).Should(gomega.Equal([]string{"added", "skipped", "skipped", "added", "skipped"}))
+	g.Expect(messages).Should(gomega.Equal([]string{
+		"",
+		`Excluded by --skip "B"`,
+		`Excluded by source.exclude "C$"`,
+		"",
+		`Not included by source.include "^Test[AB]$", "other/pkg"`,
+	}))
+}
+
+func TestInvalidTestFilter(t *testing.T) {
+	g := gomega.NewWithT(t)
+	_, err := newTestFilters(&config.ExecutionSource{}, &Arguments{run: []string{"Test("}})
+	g.Expect(err).ShouldNot(gomega.BeNil())
+	g.Expect(err.Error()).Should(gomega.HavePrefix(`invalid --run expression "Test("`))
+}
+
+func TestCountAppliesAfterFiltering(t *testing.T) {
+	g := gomega.NewWithT(t)
+	execution := &config.Execution{Name: "exec"}
+	ctx := &executionContext{
+		arguments: &Arguments{count: 1},
+		clusters: []*clustersGroup{{
+			config:    &config.ClusterProviderConfig{Name: "a_provider"},
+			tasks:     map[string]*testTask{},
+			completed: map[string]*testTask{},
+		}},
+		tests: []*model.TestEntry{
+			{Name: "TestFiltered", ExecutionConfig: execution, Status: model.StatusSkipped, SkipMessage: "Excluded by --skip"},
+			{Name: "TestFirst", ExecutionConfig: execution},
+			{Name: "TestSecond", ExecutionConfig: execution},
+		},
+	}
+	ctx.createTasks()
+
+	g.Expect(len(ctx.tasks)).Should(gomega.Equal(1))
+	g.Expect(ctx.tasks[0].test.Name).Should(gomega.Equal("TestFirst"))
+	g.Expect(len(ctx.skipped)).Should(gomega.Equal(2))
+	g.Expect(ctx.skipped[0].test.SkipMessage).Should(gomega.Equal("Excluded by --skip"))
+	g.Expect(ctx.skipped[1].test.Name).Should(gomega.Equal("TestSecond"))
+	g.Expect(ctx.skipped[1].test.Status).Should(gomega.Equal(model.StatusSkipped))
+}
@@ -0,0 +1,392 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/execmanager"
+	"github.com/denis-tingajkin/cloudtest/pkg/model"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+// configErrors - a list of configuration problems, reported all at once.
+type configErrors []string
+
+func (e configErrors) Error() string {
+	return fmt.Sprintf("configuration has %d problem(s):\n\t%s", len(e), strings.Join(e, "\n\t"))
+}
+
+func (e *configErrors) add(format string, args ...interface{}) {
+	*e = append(*e, fmt.Sprintf(format, args...))
+}
+
+// appendConfigErrors - append problems from err to list, return false if err is not a list of configuration problems.
+func appendConfigErrors(problems *configErrors, err error) bool {
+	if err == nil {
+		return true
+	}
+	if errs, ok := err.(configErrors); ok {
+		*problems = append(*problems, errs...)
+		return true
+	}
+	return false
+}
+
+// loadEffectiveConfig - load configuration file with all imports, apply profile and overrides, resolve providers
+// inheritance, expand execution matrices and add top level env files to providers and executions.
+// In case of configErrors returned, configuration is loaded but has problems.
+func loadEffectiveConfig(arguments *Arguments) (*config.CloudTestConfig, configSources, error) {
+	testConfig, sources, err := loadConfig(arguments.providerConfig)
+	var problems configErrors
+	if !appendConfigErrors(&problems, err) {
+		return nil, nil, err
+	}
+	appendConfigErrors(&problems, applyOverrides(testConfig, sources, arguments))
+	appendConfigErrors(&problems, resolveExtends(testConfig))
+	appendConfigErrors(&problems, expandMatrix(testConfig, sources))
+	applyEnvFiles(testConfig)
+	if len(problems) > 0 {
+		return testConfig, sources, problems
+	}
+	return testConfig, sources, nil
+}
+
+// loadAndValidateConfig - load effective configuration and perform pre-flight checks of it.
+func loadAndValidateConfig(arguments *Arguments) (*config.CloudTestConfig, error) {
+	testConfig, _, err := loadEffectiveConfig(arguments)
+	var problems configErrors
+	if !appendConfigErrors(&problems, err) {
+		return nil, err
+	}
+	if err = validateConfig(testConfig, arguments); !appendConfigErrors(&problems, err) {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return testConfig, nil
+}
+
+// applyOverrides - apply configuration profile, values set with environment variables and all path=value overrides
+// passed from command line.
+func applyOverrides(testConfig *config.CloudTestConfig, sources configSources, arguments *Arguments) error {
+	var problems configErrors
+	if arguments.profile != "" {
+		if err := testConfig.ApplyProfile(arguments.profile); err != nil {
+			problems.add("%v", err)
+		} else {
+			values, _ := testConfig.Profiles[arguments.profile].Values()
+			for p := range values {
+				sources[p] = "profile " + arguments.profile
+			}
+		}
+	}
+	for _, p := range envConfigPaths {
+		if value, ok := os.LookupEnv(envName(p)); ok {
+			if err := config.SetValue(testConfig, p, value); err != nil {
+				problems.add("%s %v", envName(p), err)
+				continue
+			}
+			sources[p] = "env " + envName(p)
+		}
+	}
+	for _, override := range arguments.overrides {
+		pos := strings.Index(override, "=")
+		if pos == -1 {
+			problems.add("invalid override %q, expected path=value", override)
+			continue
+		}
+		if err := config.SetValue(testConfig, override[:pos], override[pos+1:]); err != nil {
+			problems.add("--set %v", err)
+			continue
+		}
+		sources[override[:pos]] = "--set"
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// resolveExtends - merge every provider extending another one with its parent, parents are resolved first.
+func resolveExtends(testConfig *config.CloudTestConfig) error {
+	var problems configErrors
+	providers := map[string]*config.ClusterProviderConfig{}
+	for _, cl := range testConfig.Providers {
+		providers[cl.Name] = cl
+	}
+	// Result of every provider resolved, a problem is reported only once for every provider.
+	resolved := map[string]bool{}
+	var resolve func(cl *config.ClusterProviderConfig, chain []string) bool
+	resolve = func(cl *config.ClusterProviderConfig, chain []string) bool {
+		if ok, done := resolved[cl.Name]; done || cl.Extends == "" {
+			return ok || !done
+		}
+		chain = append(chain, cl.Name)
+		for idx, name := range chain[:len(chain)-1] {
+			if name == cl.Name {
+				problems.add("provider extends cycle detected: %s", strings.Join(chain[idx:], " -> "))
+				return false
+			}
+		}
+		parent, ok := providers[cl.Extends]
+		if !ok {
+			problems.add("provider %q extends unknown provider %q", cl.Name, cl.Extends)
+		} else if ok = resolve(parent, chain); ok {
+			cl.Extend(parent)
+		}
+		resolved[cl.Name] = ok
+		return ok
+	}
+	for _, cl := range testConfig.Providers {
+		resolve(cl, nil)
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// expandMatrix - replace executions with matrix defined by all their variants, variants have same source as execution.
+func expandMatrix(testConfig *config.CloudTestConfig, sources configSources) error {
+	var problems configErrors
+	var executions []*config.Execution
+	for _, exec := range testConfig.Executions {
+		variants, err := exec.ExpandMatrix()
+		if err != nil {
+			problems.add("%v", err)
+			executions = append(executions, exec)
+			continue
+		}
+		for _, variant := range variants {
+			if source, ok := sources["executions."+exec.Name]; ok && variant != exec {
+				sources["executions."+variant.Name] = source
+			}
+		}
+		executions = append(executions, variants...)
+	}
+	testConfig.Executions = executions
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// applyEnvFiles - put top level env files before env files of every provider and execution.
+func applyEnvFiles(testConfig *config.CloudTestConfig) {
+	if len(testConfig.EnvFile) == 0 {
+		return
+	}
+	for _, cl := range testConfig.Providers {
+		cl.EnvFile = append(append(config.EnvFiles{}, testConfig.EnvFile...), cl.EnvFile...)
+	}
+	for _, exec := range testConfig.Executions {
+		exec.EnvFile = append(append(config.EnvFiles{}, testConfig.EnvFile...), exec.EnvFile...)
+	}
+}
+
+// checkEnvFiles - check required env files exist and all existing ones could be parsed, paths with variables or
+// arguments are resolved on cluster start only, so they are not checked.
+func checkEnvFiles(envFiles config.EnvFiles) []string {
+	var result []string
+	for _, envFile := range envFiles {
+		if strings.Contains(envFile.Path, "$") {
+			continue
+		}
+		_, err := utils.ReadEnvFile(envFile.Path)
+		if os.IsNotExist(err) {
+			if !envFile.Optional {
+				result = append(result, fmt.Sprintf("env-file %s is not found", envFile.Path))
+			}
+			continue
+		}
+		if err != nil {
+			result = append(result, fmt.Sprintf("env-file %v", err))
+		}
+	}
+	return result
+}
+
+// validateConfig - check configuration is consistent and all enabled providers are fit, all found problems are returned.
+func validateConfig(testConfig *config.CloudTestConfig, arguments *Arguments) error {
+	var problems configErrors
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-validate")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	clusterProviders, err := createClusterProviders(execmanager.NewExecutionManager(tmpDir))
+	if err != nil {
+		return err
+	}
+
+	providerNames := map[string]bool{}
+	for idx, cl := range testConfig.Providers {
+		if cl.Name == "" {
+			problems.add("provider #%d: name should be specified", idx+1)
+			continue
+		}
+		providerNames[cl.Name] = true
+
+		provider, ok := clusterProviders[cl.Kind]
+		if !ok {
+			problems.add("provider %q: unknown kind %q", cl.Name, cl.Kind)
+			continue
+		}
+		if cl.Shell != "" && cl.Shell != utils.ShellBash {
+			problems.add("provider %q: unknown shell %q, only %s is supported", cl.Name, cl.Shell, utils.ShellBash)
+		}
+		if !isProviderEnabled(cl, arguments) {
+			continue
+		}
+		if cl.Instances <= 0 {
+			problems.add("provider %q: no instances are specified", cl.Name)
+		}
+		for overrideIdx, o := range cl.InstanceOverrides {
+			matched := false
+			for i := 1; i <= cl.Instances; i++ {
+				matched = matched || o.Matches(i, cl.InstanceID(i))
+			}
+			if !matched {
+				problems.add("provider %q: instance override #%d does not match any instance, index from 1 to %d or name like %s is expected",
+					cl.Name, overrideIdx+1, cl.Instances, cl.InstanceID(1))
+			}
+		}
+		for _, key := range sortedScriptKeys(cl.Scripts) {
+			if err := cl.Scripts[key].Validate(); err != nil {
+				problems.add("provider %q: script %s: %v", cl.Name, key, err)
+			}
+		}
+		for _, problem := range checkEnvFiles(cl.EnvFile) {
+			problems.add("provider %q: %s", cl.Name, problem)
+		}
+		if err := provider.ValidateConfig(cl); err != nil {
+			problems.add("provider %q: %v", cl.Name, err)
+		}
+	}
+
+	for idx, exec := range testConfig.Executions {
+		name := exec.Name
+		if name == "" {
+			problems.add("execution #%d: name should be specified", idx+1)
+			name = fmt.Sprintf("#%d", idx+1)
+		}
+		if exec.Kind != "" && exec.Kind != "gotest" && exec.Kind != "shell" {
+			problems.add("execution %q: unknown kind %q", name, exec.Kind)
+		}
+		if len(exec.Scripts) > 0 && exec.Kind != "shell" {
+			problems.add("execution %q: scripts could be used only by shell execution", name)
+		}
+		if exec.Format != "" && (exec.Format != "tap" || exec.Kind != "shell") {
+			problems.add("execution %q: unknown format %q, only tap format of shell execution is supported", name, exec.Format)
+		}
+		if exec.Shell != "" && exec.Shell != utils.ShellBash {
+			problems.add("execution %q: unknown shell %q, only %s is supported", name, exec.Shell, utils.ShellBash)
+		}
+		if len(exec.Scripts) > 0 && len(exec.Run) > 0 {
+			problems.add("execution %q: run and scripts could not be used together", name)
+		}
+		for _, script := range []struct {
+			name   string
+			script config.Script
+		}{{"before", exec.Before}, {"after", exec.After}, {"run", exec.Run}, {"on-fail", exec.OnFail}} {
+			if err := script.script.Validate(); err != nil {
+				problems.add("execution %q: %s: %v", name, script.name, err)
+			}
+		}
+		for _, problem := range checkEnvFiles(exec.EnvFile) {
+			problems.add("execution %q: %s", name, problem)
+		}
+		for _, sel := range exec.ClusterSelector {
+			if !providerNames[sel] {
+				problems.add("execution %q: cluster-selector %q does not match any defined provider", name, sel)
+			}
+		}
+		clusterCount := exec.ClusterCount
+		if clusterCount < 1 {
+			clusterCount = 1
+		}
+		if clusterCount > 1 && len(exec.ClusterSelector) < clusterCount {
+			problems.add("execution %q: cluster-count is %d, but only %d cluster(s) selected by cluster-selector",
+				name, clusterCount, len(exec.ClusterSelector))
+		}
+		if len(exec.KubernetesEnv) > 0 && len(exec.KubernetesEnv) != clusterCount {
+			problems.add("execution %q: kubernetes-env defines %d variable(s), but cluster-count is %d",
+				name, len(exec.KubernetesEnv), clusterCount)
+		}
+		if _, err := model.ParseTagExpressions(exec.Source.Tags); err != nil {
+			problems.add("execution %q: %v", name, err)
+		}
+		if _, err := newTestFilters(&exec.Source, &Arguments{}); err != nil {
+			problems.add("execution %q: %v", name, err)
+		}
+	}
+	if _, err := newTestFilters(&config.ExecutionSource{}, arguments); err != nil {
+		problems.add("%v", err)
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+func sortedScriptKeys(scripts map[string]config.Script) []string {
+	var keys []string
+	for key := range scripts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isProviderEnabled(cl *config.ClusterProviderConfig, arguments *Arguments) bool {
+	enabled := cl.Enabled && !arguments.onlyEnabled
+	for _, cc := range arguments.clusters {
+		if cl.Name == cc {
+			enabled = true
+		}
+	}
+	return enabled
+}
+
+func newValidateCmd(rootCmd *cloudTestCmd) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Validate cloud_test configuration file",
+		Long: `Strictly parse configuration file with all imports, check all enabled providers configuration,
+cluster selectors and kubernetes environment variables of executions. All found problems are reported at once.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if _, err := loadAndValidateConfig(rootCmd.cmdArguments); err != nil {
+				logrus.Errorf("%v", err)
+				os.Exit(1)
+			}
+			logrus.Infof("configuration %v is valid", rootCmd.cmdArguments.providerConfig)
+		},
+	}
+}
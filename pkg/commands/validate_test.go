@@ -0,0 +1,283 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+const invalidConfig = `---
+version: 1.0
+providers:
+  - name: "a_provider"
+    kind: "shell"
+    instances: 1
+    average-start-time: 5
+    enabled: true
+    scripts:
+      config: "echo ./.tests/config"
+      start: "echo started"
+    shell: "zsh"
+executions:
+  - name: "simple"
+    cluster-count: 2
+    cluster-selector:
+      - a_provider
+      - b_provider
+    cluster-env:
+      - KUBECONFIG
+    kubernetes-env:
+      - KUBECONFIG
+    shell: "sh"
+    before:
+      - name: prepare
+        run: echo prepare
+      - name: prepare
+        retries: -1
+`
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer utils.ClearFolder(tmpDir, false)
+
+	configFile := path.Join(tmpDir, "cloudtest.yaml")
+	g.Expect(ioutil.WriteFile(configFile, []byte(invalidConfig), os.ModePerm)).Should(gomega.BeNil())
+
+	_, err = loadAndValidateConfig(&Arguments{providerConfig: configFile})
+	g.Expect(err).ShouldNot(gomega.BeNil())
+	problems, ok := err.(configErrors)
+	g.Expect(ok).Should(gomega.BeTrue())
+	g.Expect(problems).Should(gomega.ConsistOf(
+		configFile+":7: field average-start-time not found in type config.ClusterProviderConfig",
+		configFile+":19: field cluster-env not found in type config.Execution",
+		`provider "a_provider": unknown shell "zsh", only bash is supported`,
+		`provider "a_provider": invalid shutdown script location`,
+		`execution "simple": cluster-selector "b_provider" does not match any defined provider`,
+		`execution "simple": kubernetes-env defines 1 variable(s), but cluster-count is 2`,
+		`execution "simple": unknown shell "sh", only bash is supported`,
+		`execution "simple": before: step #2: run should be specified`,
+	))
+}
+
+func TestValidateSkipsDisabledProviders(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer utils.ClearFolder(tmpDir, false)
+
+	configFile := path.Join(tmpDir, "cloudtest.yaml")
+	g.Expect(ioutil.WriteFile(configFile, []byte(`---
+providers:
+  - name: "a_provider"
+    kind: "shell"
+    instances: 1
+    enabled: true
+executions:
+  - name: "simple"
+`), os.ModePerm)).Should(gomega.BeNil())
+
+	cfg, err := loadAndValidateConfig(&Arguments{providerConfig: configFile, onlyEnabled: true})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(len(cfg.Executions)).Should(gomega.Equal(1))
+}
+
+func TestValidateAppliesProfileAndOverrides(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer utils.ClearFolder(tmpDir, false)
+
+	configFile := path.Join(tmpDir, "cloudtest.yaml")
+	g.Expect(ioutil.WriteFile(configFile, []byte(`---
+providers:
+  - name: "a_provider"
+    kind: "shell"
+    instances: 1
+executions:
+  - name: "simple"
+    timeout: 60
+profiles:
+  nightly:
+    executions.simple.timeout: 10m
+    providers:
+      a_provider:
+        instances: 2
+`), os.ModePerm)).Should(gomega.BeNil())
+
+	cfg, err := loadAndValidateConfig(&Arguments{
+		providerConfig: configFile,
+		profile:        "nightly",
+		overrides:      []string{"providers.a_provider.instances=4"},
+	})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(cfg.Executions[0].Timeout.Duration()).Should(gomega.Equal(10 * time.Minute))
+	g.Expect(cfg.Providers[0].Instances).Should(gomega.Equal(4))
+
+	_, err = loadAndValidateConfig(&Arguments{
+		providerConfig: configFile,
+		overrides:      []string{"executions.missing.timeout=1m", "timeout"},
+	})
+	g.Expect(err).Should(gomega.MatchError(configErrors{
+		`--set executions.missing.timeout: no item with name or index "missing"`,
+		`invalid override "timeout", expected path=value`,
+	}))
+}
+
+func TestValidateExpandsMatrix(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer utils.ClearFolder(tmpDir, false)
+
+	configFile := path.Join(tmpDir, "cloudtest.yaml")
+	g.Expect(ioutil.WriteFile(configFile, []byte(`---
+providers:
+  - name: "a_provider"
+    kind: "shell"
+    instances: 1
+executions:
+  - name: "simple"
+    matrix:
+      IPV6: ["on", "off"]
+`), os.ModePerm)).Should(gomega.BeNil())
+
+	cfg, sources, err := loadEffectiveConfig(&Arguments{providerConfig: configFile})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(len(cfg.Executions)).Should(gomega.Equal(2))
+	g.Expect(cfg.Executions[0].Name).Should(gomega.Equal("simple[IPV6=on]"))
+	g.Expect(cfg.Executions[1].Name).Should(gomega.Equal("simple[IPV6=off]"))
+	g.Expect(sources["executions.simple[IPV6=off]"]).Should(gomega.Equal(configFile))
+}
+
+func TestValidateAppliesEnvFiles(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer utils.ClearFolder(tmpDir, false)
+
+	configFile := path.Join(tmpDir, "cloudtest.yaml")
+	g.Expect(ioutil.WriteFile(configFile, []byte(`---
+env-file: .env
+providers:
+  - name: "a_provider"
+    kind: "shell"
+    instances: 1
+    env-file: ./kind.env
+executions:
+  - name: "simple"
+    matrix:
+      IPV6: ["on", "off"]
+`), os.ModePerm)).Should(gomega.BeNil())
+
+	cfg, _, err := loadEffectiveConfig(&Arguments{providerConfig: configFile})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(cfg.Providers[0].EnvFile).Should(gomega.Equal(config.EnvFiles{{Path: ".env"}, {Path: "./kind.env"}}))
+	g.Expect(cfg.Executions[0].EnvFile).Should(gomega.Equal(config.EnvFiles{{Path: ".env"}}))
+	g.Expect(cfg.Executions[1].EnvFile).Should(gomega.Equal(config.EnvFiles{{Path: ".env"}}))
+
+	badEnv := path.Join(tmpDir, "bad.env")
+	g.Expect(ioutil.WriteFile(badEnv, []byte("TOKEN\n"), os.ModePerm)).Should(gomega.BeNil())
+	g.Expect(ioutil.WriteFile(configFile, []byte(`---
+providers:
+  - name: "a_provider"
+    kind: "shell"
+    instances: 1
+    enabled: true
+    scripts:
+      config: "echo ./.tests/config"
+      start: "echo started"
+      stop: "echo stopped"
+    env-file:
+      - `+path.Join(tmpDir, "missing.env")+`
+      - path: `+path.Join(tmpDir, "local.env")+`
+        optional: true
+      - $(tempdir)/generated.env
+executions:
+  - name: "simple"
+    env-file: `+badEnv+`
+`), os.ModePerm)).Should(gomega.BeNil())
+	_, err = loadAndValidateConfig(&Arguments{providerConfig: configFile})
+	g.Expect(err).Should(gomega.MatchError(configErrors{
+		fmt.Sprintf(`provider "a_provider": env-file %s is not found`, path.Join(tmpDir, "missing.env")),
+		fmt.Sprintf(`execution "simple": env-file %s:1: KEY=VALUE is expected`, badEnv),
+	}))
+}
+
+func TestValidateResolvesProviderExtends(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer utils.ClearFolder(tmpDir, false)
+
+	configFile := path.Join(tmpDir, "cloudtest.yaml")
+	g.Expect(ioutil.WriteFile(configFile, []byte(`---
+providers:
+  - name: "base"
+    kind: "shell"
+    instances: 1
+    scripts:
+      config: "echo ./.tests/config"
+      start: "echo started"
+      stop: "echo stopped"
+  - name: "big"
+    extends: "base"
+    enabled: true
+    scripts:
+      start: "echo big started"
+`), os.ModePerm)).Should(gomega.BeNil())
+
+	cfg, err := loadAndValidateConfig(&Arguments{
+		providerConfig: configFile,
+		overrides:      []string{"providers.base.instances=2"},
+	})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(cfg.Providers[1].Instances).Should(gomega.Equal(2))
+	g.Expect(cfg.Providers[1].Scripts).Should(gomega.Equal(map[string]config.Script{
+		"config": config.NewScript("echo ./.tests/config"),
+		"start":  config.NewScript("echo big started"),
+		"stop":   config.NewScript("echo stopped"),
+	}))
+
+	g.Expect(ioutil.WriteFile(configFile, []byte(`---
+providers:
+  - name: "a"
+    extends: "b"
+  - name: "b"
+    extends: "a"
+  - name: "c"
+    extends: "a"
+  - name: "d"
+    extends: "missing"
+`), os.ModePerm)).Should(gomega.BeNil())
+	_, _, err = loadEffectiveConfig(&Arguments{providerConfig: configFile})
+	g.Expect(err).Should(gomega.MatchError(configErrors{
+		`provider extends cycle detected: a -> b -> a`,
+		`provider "d" extends unknown provider "missing"`,
+	}))
+}
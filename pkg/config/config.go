@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type DeviceConfig struct {
 	Plan            string `yaml:"plan"` // Plan
 	OperatingSystem string `yaml:"os"`   // Operating system
@@ -19,61 +21,87 @@ type ClusterProviderConfig struct {
 	Name       string            `yaml:"name"`       // name of provider, GKE, Azure, etc.
 	Kind       string            `yaml:"kind"`       // register provider type, 'generic', 'gke', multi-cluster
 	Instances  int               `yaml:"instances"`  // Number of required instances, executions will be split between instances.
-	Timeout    int               `yaml:"timeout"`    // Timeout for start, stop
+	Timeout    Duration          `yaml:"timeout"`    // Timeout for start, stop
 	RetryCount int               `yaml:"retry"`      // A count of start retrying steps.
 	NodeCount  int               `yaml:"node-count"` // A count of nodes should be available via API to match cluster is alive.
-	StopDelay  int64             `yaml:"stop-delay"` // A timeout after stop and starting of session again.
+	StopDelay  Duration          `yaml:"stop-delay"` // A timeout after stop and starting of session again.
 	Enabled    bool              `yaml:"enabled"`    // Is it enabled by default or not
 	Parameters map[string]string `yaml:"parameters"` // A parameters specific for provider
-	Scripts    map[string]string `yaml:"scripts"`    // A parameters specific for provider
+	Scripts    map[string]Script `yaml:"scripts"`    // A scripts of provider, like start, stop, install, a string or a list of steps
 	Env        []string          `yaml:"env"`        // Extra environment variables
+	EnvFile    EnvFiles          `yaml:"env-file"`   // A .env file or a list of them, variables of files are added before env and are masked as secrets.
 	EnvCheck   []string          `yaml:"env-check"`  // Check if environment has required environment variables present.
 	Packet     *PacketConfig     `yaml:"packet"`     // A Packet provider configuration
-	TestDelay  int               `yaml:"test-delay"` // Delay between tests of this cluster will be executed in second.
+	TestDelay  Duration          `yaml:"test-delay"` // Delay between tests of this cluster will be executed.
+	Extends    string            `yaml:"extends"`    // A name of parent provider, its configuration is merged into this one.
+	Shell      string            `yaml:"shell"`      // A shell to execute every script as a whole, like 'bash', by default scripts are executed line by line.
+
+	InstanceOverrides []*InstanceOverride `yaml:"instance-overrides"` // Configuration changes of specific instances.
+}
+
+type InstanceOverride struct {
+	Index      int               `yaml:"index"`      // An instance number starting from 1.
+	Name       string            `yaml:"name"`       // An instance name, provider name with instance number, like packet-2.
+	Env        []string          `yaml:"env"`        // Environment variables added or replaced for instance.
+	Parameters map[string]string `yaml:"parameters"` // Provider parameters added or replaced for instance.
+	NodeCount  int               `yaml:"node-count"` // A count of nodes should be available via API to match instance is alive.
+	Packet     struct {
+		PreferredFacility string `yaml:"preferred-facility"` // A prefered facility key for instance.
+	} `yaml:"packet"` // A Packet configuration changes.
 }
 
 type ExecutionSource struct {
-	Tags  []string `yaml:"tags"`  // A list of tags for this configured execution.
-	Tests []string `yaml:"tests"` // A list of tests for execution.
+	Tags    []string `yaml:"tags"`    // A list of build tag expressions like "basic && !slow", tests selected by any of them are executed.
+	Tests   []string `yaml:"tests"`   // A list of tests for execution.
+	Include []string `yaml:"include"` // A list of regular expressions, only tests matching any of them are executed.
+	Exclude []string `yaml:"exclude"` // A list of regular expressions, tests matching any of them are skipped.
 }
 
 type Execution struct {
 	Source          ExecutionSource `yaml:"source"`           // A source for tests execution
-	Before          string          `yaml:"before"`           // A script to execute against required cluster, called before run tasks from execution.
-	After           string          `yaml:"after"`            // A script to execute against required cluster, called when all tasks from execution are done on cluster instance.
+	Before          Script          `yaml:"before"`           // A script to execute against required cluster, called before run tasks from execution.
+	After           Script          `yaml:"after"`            // A script to execute against required cluster, called when all tasks from execution are done on cluster instance.
 	Kind            string          `yaml:"kind"`             // Execution kind, default is 'gotest', 'shell' could be used for pure shell tests.
 	Name            string          `yaml:"name"`             // Execution name
 	OnlyRun         []string        `yaml:"only-run"`         // If non-empty, only run the listed tests
-	PackageRoot     string          `yaml:"root"`             // A package root for this test execution, default .
-	Timeout         int64           `yaml:"timeout"`          // Invidiaul test timeout, "60" passed to gotest, in seconds
+	PackageRoot     StringList      `yaml:"root"`             // Package folders or patterns like ./test/..., every root is a folder go test is executed in, default .
+	Timeout         Duration        `yaml:"timeout"`          // Invidiaul test timeout, "60s" passed to gotest, default 3m
 	ExtraOptions    []string        `yaml:"extra-options"`    // Extra options to pass to gotest
 	ClusterCount    int             `yaml:"cluster-count"`    // A number of clusters required for this execution, default 1
 	KubernetesEnv   []string        `yaml:"kubernetes-env"`   // Names of environment variables to put cluster names inside.
 	ClusterSelector []string        `yaml:"cluster-selector"` // A cluster name to execute this tests on.
 	Env             []string        `yaml:"env"`              // Additional environment variables
-	Run             string          `yaml:"run"`              // A script to execute against required cluster
-	OnFail          string          `yaml:"on_fail"`          // A script to execute against required cluster, called if task failed
+	EnvFile         EnvFiles        `yaml:"env-file"`         // A .env file or a list of them, variables of files are added before env and are masked as secrets.
+	Run             Script          `yaml:"run"`              // A script to execute against required cluster
+	Scripts         StringList      `yaml:"scripts"`          // Folders or globs of script files of shell execution, every script is executed as a separate test
+	Format          string          `yaml:"format"`           // Output format of shell tests, with 'tap' every TAP test point is reported as a subtest
+	OnFail          Script          `yaml:"on-fail"`          // A script to execute against required cluster, called if task failed
+	Shell           string          `yaml:"shell"`            // A shell to execute every script as a whole, like 'bash', by default scripts are executed line by line.
 
 	ConcurrencyRetry int64 `yaml:"test-retry-count"` // A count of times, same test will be executed to find concurrency issues
+	ExpandSubtests   bool  `yaml:"expand-subtests"`  // Execute every subtest of go tests as a separate test, like TestParent/child
+
+	Matrix        map[string][]string `yaml:"matrix"`         // Environment variables values, execution is expanded into a variant for every combination.
+	MatrixExclude []map[string]string `yaml:"matrix-exclude"` // Combinations of matrix values to be excluded, all listed variables should match.
 }
 
 type RetestConfig struct {
 	// Executions, every execution execute some tests agains configured set of clusters
 	Patterns         []string `yaml:"pattern"`         // Restart test output pattern, to treat as a test restart request, test will be added back for execution.
 	RestartCount     int      `yaml:"count"`           // Allow to restart only few times using RestartCode check.
-	WarmupTimeout    int      `yaml:"warmup-time"`     // A cluster instance should warmup for some time if this is happening.
+	WarmupTimeout    Duration `yaml:"warmup-time"`     // A cluster instance should warmup for some time if this is happening.
 	AllowedRetests   int      `yaml:"allowed-retests"` // A number of allowed retests for cluster, if reached, cluster instance will be restarted.
 	RetestFailResult string   `yaml:"fail-result"`     // A status if all attempts are failed, usual is skipped. if value != skip, it will be failed.
 }
 
 type HealthCheckConfig struct {
-	Interval int64  `yaml:"interval"` // Interval between Health checks in seconds
-	Run      string `yaml:"run"`      // A script to execute with health check purpose
-	Message  string `yaml:"message"`
+	Interval Duration `yaml:"interval"` // Interval between Health checks
+	Run      string   `yaml:"run"`      // A script to execute with health check purpose
+	Message  string   `yaml:"message"`
 }
 
 type CloudTestConfig struct {
-	Version    string                   `yaml:"version"` // Provider file version, 1.0
+	Version    string                   `yaml:"version"` // Configuration file version, older versions are migrated to current one
 	Providers  []*ClusterProviderConfig `yaml:"providers"`
 	ConfigRoot string                   `yaml:"root"` // A provider stored configurations root.
 	Reporting  struct {
@@ -81,23 +109,26 @@ type CloudTestConfig struct {
 	} `yaml:"reporting"` // A reporting options.
 	HealthCheck []*HealthCheckConfig `yaml:"health-check"` // Health checks options.
 	Executions  []*Execution         `yaml:"executions"`
-	Timeout     int64                `yaml:"timeout"` // Global timeout in seconds
-	Imports     []string             `yaml:"import"`  // A set of configurations for import
+	Timeout     Duration             `yaml:"timeout"`  // Global timeout
+	Imports     []string             `yaml:"import"`   // A set of configurations for import, relative to this file folder
+	EnvFile     EnvFiles             `yaml:"env-file"` // A .env file or a list of them, files are loaded by every provider and execution before their own ones.
 
 	RetestConfig RetestConfig `yaml:"retest"`
 
 	Statistics struct {
-		Interval int64 `yaml:"interval"` // A statistics printing timeout, default 60 seconds
-		Enabled  bool  `yaml:"enabled"`  // A way to disable printing of statistics
+		Interval Duration `yaml:"interval"` // A statistics printing timeout, default 60 seconds
+		Enabled  bool     `yaml:"enabled"`  // A way to disable printing of statistics
 	} `yaml:"statistics"` // Statistics options
 
 	ShuffleTests bool `yaml:"shuffle-enabled"` // Shuffle tests before assignment
+
+	Profiles map[string]Profile `yaml:"profiles"` // Named configuration overlays, selected with --profile
 }
 
 // NewCloudTestConfig - creates a test config with some default values specified.
 func NewCloudTestConfig() (result *CloudTestConfig) {
 	result = &CloudTestConfig{}
 	result.Statistics.Enabled = true
-	result.Statistics.Interval = 60
+	result.Statistics.Interval = Duration(time.Minute)
 	return result
 }
@@ -0,0 +1,97 @@
+// Code generated by schemagen. DO NOT EDIT.
+
+package config
+
+// fieldDescriptions - comments of configuration struct fields, used as JSON schema descriptions.
+var fieldDescriptions = map[string]string{
+	"CloudTestConfig.ConfigRoot":                "A provider stored configurations root.",
+	"CloudTestConfig.EnvFile":                   "A .env file or a list of them, files are loaded by every provider and execution before their own ones.",
+	"CloudTestConfig.HealthCheck":               "Health checks options.",
+	"CloudTestConfig.Imports":                   "A set of configurations for import, relative to this file folder",
+	"CloudTestConfig.Profiles":                  "Named configuration overlays, selected with --profile",
+	"CloudTestConfig.Reporting":                 "A reporting options.",
+	"CloudTestConfig.Reporting.JUnitReportFile": "A junit report file location, relative to test root folder.",
+	"CloudTestConfig.ShuffleTests":              "Shuffle tests before assignment",
+	"CloudTestConfig.Statistics":                "Statistics options",
+	"CloudTestConfig.Statistics.Enabled":        "A way to disable printing of statistics",
+	"CloudTestConfig.Statistics.Interval":       "A statistics printing timeout, default 60 seconds",
+	"CloudTestConfig.Timeout":                   "Global timeout",
+	"CloudTestConfig.Version":                   "Configuration file version, older versions are migrated to current one",
+	"ClusterProviderConfig.Enabled":             "Is it enabled by default or not",
+	"ClusterProviderConfig.Env":                 "Extra environment variables",
+	"ClusterProviderConfig.EnvCheck":            "Check if environment has required environment variables present.",
+	"ClusterProviderConfig.EnvFile":             "A .env file or a list of them, variables of files are added before env and are masked as secrets.",
+	"ClusterProviderConfig.Extends":             "A name of parent provider, its configuration is merged into this one.",
+	"ClusterProviderConfig.InstanceOverrides":   "Configuration changes of specific instances.",
+	"ClusterProviderConfig.Instances":           "Number of required instances, executions will be split between instances.",
+	"ClusterProviderConfig.Kind":                "register provider type, 'generic', 'gke', multi-cluster",
+	"ClusterProviderConfig.Name":                "name of provider, GKE, Azure, etc.",
+	"ClusterProviderConfig.NodeCount":           "A count of nodes should be available via API to match cluster is alive.",
+	"ClusterProviderConfig.Packet":              "A Packet provider configuration",
+	"ClusterProviderConfig.Parameters":          "A parameters specific for provider",
+	"ClusterProviderConfig.RetryCount":          "A count of start retrying steps.",
+	"ClusterProviderConfig.Scripts":             "A scripts of provider, like start, stop, install, a string or a list of steps",
+	"ClusterProviderConfig.Shell":               "A shell to execute every script as a whole, like 'bash', by default scripts are executed line by line.",
+	"ClusterProviderConfig.StopDelay":           "A timeout after stop and starting of session again.",
+	"ClusterProviderConfig.TestDelay":           "Delay between tests of this cluster will be executed.",
+	"ClusterProviderConfig.Timeout":             "Timeout for start, stop",
+	"DeviceConfig.HostName":                     "Host name with variable substitutions supported.",
+	"DeviceConfig.Name":                         "Host name prefix, will create ENV variable IP_HostName",
+	"DeviceConfig.OperatingSystem":              "Operating system",
+	"DeviceConfig.Plan":                         "Plan",
+	"EnvFile.Optional":                          "A missing file is skipped, by default a missing file is an error.",
+	"EnvFile.Path":                              "A file location, relative to current folder, ${VAR} and $(arg) are substituted.",
+	"Execution.After":                           "A script to execute against required cluster, called when all tasks from execution are done on cluster instance.",
+	"Execution.Before":                          "A script to execute against required cluster, called before run tasks from execution.",
+	"Execution.ClusterCount":                    "A number of clusters required for this execution, default 1",
+	"Execution.ClusterSelector":                 "A cluster name to execute this tests on.",
+	"Execution.ConcurrencyRetry":                "A count of times, same test will be executed to find concurrency issues",
+	"Execution.Env":                             "Additional environment variables",
+	"Execution.EnvFile":                         "A .env file or a list of them, variables of files are added before env and are masked as secrets.",
+	"Execution.ExpandSubtests":                  "Execute every subtest of go tests as a separate test, like TestParent/child",
+	"Execution.ExtraOptions":                    "Extra options to pass to gotest",
+	"Execution.Format":                          "Output format of shell tests, with 'tap' every TAP test point is reported as a subtest",
+	"Execution.Kind":                            "Execution kind, default is 'gotest', 'shell' could be used for pure shell tests.",
+	"Execution.KubernetesEnv":                   "Names of environment variables to put cluster names inside.",
+	"Execution.Matrix":                          "Environment variables values, execution is expanded into a variant for every combination.",
+	"Execution.MatrixExclude":                   "Combinations of matrix values to be excluded, all listed variables should match.",
+	"Execution.Name":                            "Execution name",
+	"Execution.OnFail":                          "A script to execute against required cluster, called if task failed",
+	"Execution.OnlyRun":                         "If non-empty, only run the listed tests",
+	"Execution.PackageRoot":                     "Package folders or patterns like ./test/..., every root is a folder go test is executed in, default .",
+	"Execution.Run":                             "A script to execute against required cluster",
+	"Execution.Scripts":                         "Folders or globs of script files of shell execution, every script is executed as a separate test",
+	"Execution.Shell":                           "A shell to execute every script as a whole, like 'bash', by default scripts are executed line by line.",
+	"Execution.Source":                          "A source for tests execution",
+	"Execution.Timeout":                         "Invidiaul test timeout, \"60s\" passed to gotest, default 3m",
+	"ExecutionSource.Exclude":                   "A list of regular expressions, tests matching any of them are skipped.",
+	"ExecutionSource.Include":                   "A list of regular expressions, only tests matching any of them are executed.",
+	"ExecutionSource.Tags":                      "A list of build tag expressions like \"basic && !slow\", tests selected by any of them are executed.",
+	"ExecutionSource.Tests":                     "A list of tests for execution.",
+	"HealthCheckConfig.Interval":                "Interval between Health checks",
+	"HealthCheckConfig.Run":                     "A script to execute with health check purpose",
+	"InstanceOverride.Env":                      "Environment variables added or replaced for instance.",
+	"InstanceOverride.Index":                    "An instance number starting from 1.",
+	"InstanceOverride.Name":                     "An instance name, provider name with instance number, like packet-2.",
+	"InstanceOverride.NodeCount":                "A count of nodes should be available via API to match instance is alive.",
+	"InstanceOverride.Packet":                   "A Packet configuration changes.",
+	"InstanceOverride.Packet.PreferredFacility": "A prefered facility key for instance.",
+	"InstanceOverride.Parameters":               "Provider parameters added or replaced for instance.",
+	"PacketConfig.Devices":                      "A set of device configuration required to be created before starting cluster.",
+	"PacketConfig.Facilities":                   "A set of facility filters",
+	"PacketConfig.PreferredFacility":            "A prefered facility key",
+	"PacketConfig.SshKey":                       "A location of ssh key",
+	"RetestConfig.AllowedRetests":               "A number of allowed retests for cluster, if reached, cluster instance will be restarted.",
+	"RetestConfig.Patterns":                     "Restart test output pattern, to treat as a test restart request, test will be added back for execution.",
+	"RetestConfig.RestartCount":                 "Allow to restart only few times using RestartCode check.",
+	"RetestConfig.RetestFailResult":             "A status if all attempts are failed, usual is skipped. if value != skip, it will be failed.",
+	"RetestConfig.WarmupTimeout":                "A cluster instance should warmup for some time if this is happening.",
+	"ScriptStep.ContinueOnError":                "Continue with next step if step is failed.",
+	"ScriptStep.Env":                            "Extra environment variables of step.",
+	"ScriptStep.Name":                           "A step name, used in output and as a name of step log file.",
+	"ScriptStep.Outputs":                        "Names of values captured from KEY=VALUE lines of step output or $CLOUDTEST_OUTPUT file.",
+	"ScriptStep.Retries":                        "A number of attempts to run failed step again.",
+	"ScriptStep.RetryDelay":                     "A delay before every retry of failed step.",
+	"ScriptStep.Run":                            "A multi line script of step.",
+	"ScriptStep.Timeout":                        "A timeout of step, step is failed if not completed in time.",
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Duration - a configuration duration value, accepts Go duration strings like "90s", "15m", "1h30m"
+// as well as legacy integer values, treated as a number of seconds.
+type Duration time.Duration
+
+// ParseDuration - parse duration string or integer number of seconds.
+func ParseDuration(value string) (Duration, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return Duration(time.Duration(seconds) * time.Second), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Errorf("invalid duration %q, expected value like 90s, 15m, 1h30m or a number of seconds", value)
+	}
+	return Duration(d), nil
+}
+
+// Duration - return value as time.Duration
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String - return Go duration string representation.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// UnmarshalYAML - read duration from integer number of seconds or duration string.
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+	result, err := ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = result
+	return nil
+}
+
+// MarshalYAML - write duration as Go duration string.
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+func TestDurationUnmarshal(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"300":     300 * time.Second,
+		"\"300\"": 300 * time.Second,
+		"90s":     90 * time.Second,
+		"15m":     15 * time.Minute,
+		"1h30m":   90 * time.Minute,
+	} {
+		g := gomega.NewWithT(t)
+		execution := &Execution{}
+		err := yaml.UnmarshalStrict([]byte("timeout: "+value), execution)
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(execution.Timeout.Duration()).Should(gomega.Equal(expected), value)
+	}
+}
+
+func TestDurationInvalid(t *testing.T) {
+	g := gomega.NewWithT(t)
+	execution := &Execution{}
+	err := yaml.UnmarshalStrict([]byte("timeout: 15 minutes"), execution)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+	g.Expect(err.Error()).Should(gomega.ContainSubstring(`invalid duration "15 minutes"`))
+}
+
+func TestDurationMarshal(t *testing.T) {
+	g := gomega.NewWithT(t)
+	out, err := yaml.Marshal(&HealthCheckConfig{Interval: Duration(90 * time.Second)})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(string(out)).Should(gomega.ContainSubstring("interval: 1m30s"))
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+// EnvFile - a .env file with KEY=VALUE environment variables.
+type EnvFile struct {
+	Path     string `yaml:"path"`     // A file location, relative to current folder, ${VAR} and $(arg) are substituted.
+	Optional bool   `yaml:"optional"` // A missing file is skipped, by default a missing file is an error.
+}
+
+// EnvFiles - a list of env files, accepts a single file path as well as a list of paths and env file objects.
+type EnvFiles []*EnvFile
+
+// UnmarshalYAML - read env file from a path or an object.
+func (f *EnvFile) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err == nil {
+		*f = EnvFile{Path: value}
+		return nil
+	}
+	type envFile EnvFile
+	return unmarshal((*envFile)(f))
+}
+
+// MarshalYAML - write required env file as a path.
+func (f *EnvFile) MarshalYAML() (interface{}, error) {
+	if !f.Optional {
+		return f.Path, nil
+	}
+	type envFile EnvFile
+	return (*envFile)(f), nil
+}
+
+// UnmarshalYAML - read list from a single path or a list of env files.
+func (l *EnvFiles) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err == nil {
+		*l = EnvFiles{{Path: value}}
+		return nil
+	}
+	var values []*EnvFile
+	if err := unmarshal(&values); err != nil {
+		return err
+	}
+	*l = values
+	return nil
+}
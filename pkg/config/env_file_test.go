@@ -0,0 +1,55 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+func TestEnvFilesUnmarshal(t *testing.T) {
+	g := gomega.NewWithT(t)
+	cfg := &CloudTestConfig{}
+	err := yaml.UnmarshalStrict([]byte(`
+env-file: .env
+executions:
+  - name: smoke
+    env-file:
+      - ./smoke.env
+      - path: ./local.env
+        optional: true
+`), cfg)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(cfg.EnvFile).Should(gomega.Equal(EnvFiles{{Path: ".env"}}))
+	g.Expect(cfg.Executions[0].EnvFile).Should(gomega.Equal(EnvFiles{
+		{Path: "./smoke.env"},
+		{Path: "./local.env", Optional: true},
+	}))
+
+	out, err := yaml.Marshal(cfg.Executions[0].EnvFile)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(string(out)).Should(gomega.Equal("- ./smoke.env\n- path: ./local.env\n  optional: true\n"))
+
+	err = yaml.UnmarshalStrict([]byte(`
+env-file:
+  - path: .env
+    required: true
+`), cfg)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Extend - merge provider with its parent provider. Scripts, parameters and env are merged by key with provider
+// values taking precedence, packet block is merged field by field and its devices are merged by name.
+// All other fields not set in provider are inherited from parent, except name and enabled.
+func (p *ClusterProviderConfig) Extend(parent *ClusterProviderConfig) {
+	value := reflect.ValueOf(p).Elem()
+	parentValue := reflect.ValueOf(parent).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		switch value.Type().Field(i).Name {
+		case "Name", "Enabled", "Extends", "Scripts", "Parameters", "Env", "Packet":
+			continue
+		}
+		if field := value.Field(i); isZero(field) {
+			field.Set(parentValue.Field(i))
+		}
+	}
+	p.Scripts = mergeScripts(parent.Scripts, p.Scripts)
+	p.Parameters = mergeMaps(parent.Parameters, p.Parameters)
+	p.Env = mergeEnv(parent.Env, p.Env)
+	p.Packet = mergePacket(parent.Packet, p.Packet)
+}
+
+func isZero(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	}
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
+
+func mergeMaps(parent, values map[string]string) map[string]string {
+	if parent == nil {
+		return values
+	}
+	result := map[string]string{}
+	for key, value := range parent {
+		result[key] = value
+	}
+	for key, value := range values {
+		result[key] = value
+	}
+	return result
+}
+
+func mergeScripts(parent, values map[string]Script) map[string]Script {
+	if parent == nil {
+		return values
+	}
+	result := map[string]Script{}
+	for key, value := range parent {
+		result[key] = value
+	}
+	for key, value := range values {
+		result[key] = value
+	}
+	return result
+}
+
+// mergeEnv - merge KEY=VALUE lists, parent variables redefined in values are replaced in place.
+func mergeEnv(parent, values []string) []string {
+	if len(parent) == 0 {
+		return values
+	}
+	envKey := func(env string) string {
+		return strings.SplitN(env, "=", 2)[0]
+	}
+	result := append([]string{}, parent...)
+	positions := map[string]int{}
+	for idx, env := range result {
+		positions[envKey(env)] = idx
+	}
+	for _, env := range values {
+		if idx, ok := positions[envKey(env)]; ok {
+			result[idx] = env
+			continue
+		}
+		positions[envKey(env)] = len(result)
+		result = append(result, env)
+	}
+	return result
+}
+
+// mergePacket - return a copy of parent packet configuration with fields set in values replaced.
+func mergePacket(parent, values *PacketConfig) *PacketConfig {
+	if parent == nil {
+		return values
+	}
+	result := *parent
+	if values == nil {
+		return &result
+	}
+	result.Devices = append([]*DeviceConfig{}, parent.Devices...)
+	for _, device := range values.Devices {
+		replaced := false
+		for idx, d := range result.Devices {
+			if d.Name == device.Name {
+				result.Devices[idx] = device
+				replaced = true
+			}
+		}
+		if !replaced {
+			result.Devices = append(result.Devices, device)
+		}
+	}
+	if len(values.Facilities) > 0 {
+		result.Facilities = values.Facilities
+	}
+	if values.PreferredFacility != "" {
+		result.PreferredFacility = values.PreferredFacility
+	}
+	if values.SshKey != "" {
+		result.SshKey = values.SshKey
+	}
+	return &result
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+func TestExtendProvider(t *testing.T) {
+	g := gomega.NewWithT(t)
+	var providers []*ClusterProviderConfig
+	g.Expect(yaml.UnmarshalStrict([]byte(`
+- name: packet
+  kind: packet
+  instances: 2
+  timeout: 10m
+  node-count: 2
+  enabled: true
+  env:
+    - CLUSTER_TYPE=kubeadm
+    - KUBE_VERSION=1.16
+  parameters:
+    project: nsm
+  scripts:
+    install: "install.sh"
+    start: "start.sh"
+  packet:
+    ssh-key: "key"
+    facilities: [sjc1, ams1]
+    devices:
+      - name: master
+        plan: t1.small
+      - name: worker
+        plan: t1.small
+- name: packet-big
+  extends: packet
+  node-count: 3
+  env:
+    - KUBE_VERSION=1.17
+    - EXTRA=true
+  scripts:
+    start: "start-big.sh"
+  packet:
+    preferred-facility: ams1
+    devices:
+      - name: worker
+        plan: c1.large
+`), &providers)).Should(gomega.BeNil())
+
+	child := providers[1]
+	child.Extend(providers[0])
+
+	g.Expect(child.Name).Should(gomega.Equal("packet-big"))
+	g.Expect(child.Enabled).Should(gomega.BeFalse())
+	g.Expect(child.Kind).Should(gomega.Equal("packet"))
+	g.Expect(child.Instances).Should(gomega.Equal(2))
+	g.Expect(child.Timeout.Duration()).Should(gomega.Equal(10 * time.Minute))
+	g.Expect(child.NodeCount).Should(gomega.Equal(3))
+	g.Expect(child.Env).Should(gomega.Equal([]string{"CLUSTER_TYPE=kubeadm", "KUBE_VERSION=1.17", "EXTRA=true"}))
+	g.Expect(child.Parameters).Should(gomega.Equal(map[string]string{"project": "nsm"}))
+	g.Expect(child.Scripts).Should(gomega.Equal(map[string]Script{"install": NewScript("install.sh"), "start": NewScript("start-big.sh")}))
+	g.Expect(child.Packet.SshKey).Should(gomega.Equal("key"))
+	g.Expect(child.Packet.Facilities).Should(gomega.Equal([]string{"sjc1", "ams1"}))
+	g.Expect(child.Packet.PreferredFacility).Should(gomega.Equal("ams1"))
+	g.Expect(len(child.Packet.Devices)).Should(gomega.Equal(2))
+	g.Expect(child.Packet.Devices[1].Plan).Should(gomega.Equal("c1.large"))
+
+	// Parent is not changed
+	g.Expect(providers[0].Scripts["start"].String()).Should(gomega.Equal("start.sh"))
+	g.Expect(providers[0].Packet.Devices[1].Plan).Should(gomega.Equal("t1.small"))
+	g.Expect(providers[0].Packet.PreferredFacility).Should(gomega.Equal(""))
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InstanceID - return an id of provider instance with passed number, starting from 1.
+func (p *ClusterProviderConfig) InstanceID(index int) string {
+	return fmt.Sprintf("%s-%d", p.Name, index)
+}
+
+// Matches - check if override is applied to provider instance with passed number, starting from 1, and id.
+func (o *InstanceOverride) Matches(index int, id string) bool {
+	return (o.Index != 0 || o.Name != "") && (o.Index == 0 || o.Index == index) && (o.Name == "" || o.Name == id)
+}
+
+// ForInstance - return configuration of provider instance with passed number, starting from 1, with all matching
+// instance overrides applied, and a description of applied overrides, empty if there are none.
+func (p *ClusterProviderConfig) ForInstance(index int) (*ClusterProviderConfig, string) {
+	result := *p
+	id := p.InstanceID(index)
+	var applied []string
+	for _, o := range p.InstanceOverrides {
+		if !o.Matches(index, id) {
+			continue
+		}
+		if len(o.Env) > 0 {
+			result.Env = mergeEnv(result.Env, o.Env)
+			var names []string
+			for _, env := range o.Env {
+				names = append(names, strings.SplitN(env, "=", 2)[0])
+			}
+			applied = append(applied, "env "+strings.Join(names, ", "))
+		}
+		if len(o.Parameters) > 0 {
+			result.Parameters = mergeMaps(result.Parameters, o.Parameters)
+			var names []string
+			for key := range o.Parameters {
+				names = append(names, key)
+			}
+			sort.Strings(names)
+			applied = append(applied, "parameters "+strings.Join(names, ", "))
+		}
+		if o.NodeCount > 0 {
+			result.NodeCount = o.NodeCount
+			applied = append(applied, fmt.Sprintf("node-count %d", o.NodeCount))
+		}
+		if o.Packet.PreferredFacility != "" {
+			result.Packet = mergePacket(result.Packet, &PacketConfig{PreferredFacility: o.Packet.PreferredFacility})
+			applied = append(applied, "packet.preferred-facility "+o.Packet.PreferredFacility)
+		}
+	}
+	return &result, strings.Join(applied, "; ")
+}
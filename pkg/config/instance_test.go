@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+func TestProviderForInstance(t *testing.T) {
+	g := gomega.NewWithT(t)
+	provider := &ClusterProviderConfig{}
+	g.Expect(yaml.UnmarshalStrict([]byte(`
+name: packet
+instances: 3
+node-count: 2
+env:
+  - ZONE=a
+parameters:
+  plan: small
+packet:
+  facilities: [sjc1, ams1]
+instance-overrides:
+  - index: 2
+    env:
+      - ZONE=b
+    packet:
+      preferred-facility: ams1
+  - name: packet-3
+    node-count: 4
+    parameters:
+      plan: large
+`), provider)).Should(gomega.BeNil())
+
+	first, overrides := provider.ForInstance(1)
+	g.Expect(overrides).Should(gomega.BeEmpty())
+	g.Expect(first.Env).Should(gomega.Equal([]string{"ZONE=a"}))
+
+	second, overrides := provider.ForInstance(2)
+	g.Expect(overrides).Should(gomega.Equal("env ZONE; packet.preferred-facility ams1"))
+	g.Expect(second.Env).Should(gomega.Equal([]string{"ZONE=b"}))
+	g.Expect(second.NodeCount).Should(gomega.Equal(2))
+	g.Expect(second.Packet.PreferredFacility).Should(gomega.Equal("ams1"))
+	g.Expect(second.Packet.Facilities).Should(gomega.Equal([]string{"sjc1", "ams1"}))
+
+	third, overrides := provider.ForInstance(3)
+	g.Expect(overrides).Should(gomega.Equal("parameters plan; node-count 4"))
+	g.Expect(third.NodeCount).Should(gomega.Equal(4))
+	g.Expect(third.Parameters).Should(gomega.Equal(map[string]string{"plan": "large"}))
+
+	// Provider configuration is not changed
+	g.Expect(provider.Env).Should(gomega.Equal([]string{"ZONE=a"}))
+	g.Expect(provider.Packet.PreferredFacility).Should(gomega.BeEmpty())
+	g.Expect(provider.Parameters).Should(gomega.Equal(map[string]string{"plan": "small"}))
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ExpandMatrix - return an execution variant for every combination of matrix values, except excluded ones.
+// Every variant has matrix values added to environment and a name like "basic[INSECURE=true,IPV6=on]",
+// variables are sorted by name. Execution itself is returned if it has no matrix defined.
+func (e *Execution) ExpandMatrix() ([]*Execution, error) {
+	if len(e.Matrix) == 0 {
+		if len(e.MatrixExclude) > 0 {
+			return nil, errors.Errorf("execution %q: matrix-exclude is defined, but matrix is empty", e.Name)
+		}
+		return []*Execution{e}, nil
+	}
+
+	var keys []string
+	for key, values := range e.Matrix {
+		if len(values) == 0 {
+			return nil, errors.Errorf("execution %q: matrix variable %s has no values", e.Name, key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, exclude := range e.MatrixExclude {
+		for key := range exclude {
+			if _, ok := e.Matrix[key]; !ok {
+				return nil, errors.Errorf("execution %q: matrix-exclude refers to unknown matrix variable %s", e.Name, key)
+			}
+		}
+	}
+
+	var result []*Execution
+	combination := map[string]string{}
+	var expand func(idx int)
+	expand = func(idx int) {
+		if idx == len(keys) {
+			if !e.isExcluded(combination) {
+				result = append(result, e.variant(keys, combination))
+			}
+			return
+		}
+		for _, value := range e.Matrix[keys[idx]] {
+			combination[keys[idx]] = value
+			expand(idx + 1)
+		}
+	}
+	expand(0)
+
+	if len(result) == 0 {
+		return nil, errors.Errorf("execution %q: all matrix combinations are excluded", e.Name)
+	}
+	return result, nil
+}
+
+func (e *Execution) isExcluded(combination map[string]string) bool {
+	for _, exclude := range e.MatrixExclude {
+		match := true
+		for key, value := range exclude {
+			match = match && combination[key] == value
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Execution) variant(keys []string, combination map[string]string) *Execution {
+	result := *e
+	result.Matrix = nil
+	result.MatrixExclude = nil
+	result.Env = append([]string{}, e.Env...)
+	var values []string
+	for _, key := range keys {
+		values = append(values, fmt.Sprintf("%s=%s", key, combination[key]))
+	}
+	result.Env = append(result.Env, values...)
+	result.Name = fmt.Sprintf("%s[%s]", e.Name, strings.Join(values, ","))
+	return &result
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+func TestExpandMatrix(t *testing.T) {
+	g := gomega.NewWithT(t)
+	execution := &Execution{}
+	g.Expect(yaml.UnmarshalStrict([]byte(`
+name: basic
+env:
+  - A=B
+matrix:
+  INSECURE: [true, false]
+  IPV6: ["on", "off"]
+matrix-exclude:
+  - INSECURE: false
+    IPV6: "off"
+`), execution)).Should(gomega.BeNil())
+
+	variants, err := execution.ExpandMatrix()
+	g.Expect(err).Should(gomega.BeNil())
+
+	env := map[string][]string{}
+	for _, v := range variants {
+		g.Expect(v.Matrix).Should(gomega.BeNil())
+		env[v.Name] = v.Env
+	}
+	g.Expect(env).Should(gomega.Equal(map[string][]string{
+		"basic[INSECURE=true,IPV6=on]":  {"A=B", "INSECURE=true", "IPV6=on"},
+		"basic[INSECURE=true,IPV6=off]": {"A=B", "INSECURE=true", "IPV6=off"},
+		"basic[INSECURE=false,IPV6=on]": {"A=B", "INSECURE=false", "IPV6=on"},
+	}))
+	g.Expect(execution.Env).Should(gomega.Equal([]string{"A=B"}))
+}
+
+func TestExpandMatrixErrors(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	variants, err := (&Execution{Name: "plain"}).ExpandMatrix()
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(len(variants)).Should(gomega.Equal(1))
+
+	_, err = (&Execution{
+		Name:          "basic",
+		Matrix:        map[string][]string{"A": {"1"}},
+		MatrixExclude: []map[string]string{{"B": "1"}},
+	}).ExpandMatrix()
+	g.Expect(err).Should(gomega.MatchError(`execution "basic": matrix-exclude refers to unknown matrix variable B`))
+
+	_, err = (&Execution{
+		Name:          "basic",
+		Matrix:        map[string][]string{"A": {"1"}},
+		MatrixExclude: []map[string]string{{"A": "1"}},
+	}).ExpandMatrix()
+	g.Expect(err).Should(gomega.MatchError(`execution "basic": all matrix combinations are excluded`))
+}
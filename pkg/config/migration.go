@@ -0,0 +1,285 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// CurrentVersion - a version of configuration file layout, older versions are migrated to it.
+	CurrentVersion = "1.1"
+	// defaultVersion - a version assumed if configuration file does not specify it.
+	defaultVersion = "1.0"
+)
+
+// SupportedVersions - all configuration file versions could be loaded.
+var SupportedVersions = []string{"1.0", CurrentVersion}
+
+// RenamedFields - legacy field names renamed by migrations, keyed by old name.
+var RenamedFields = map[string]string{
+	"on_fail": "on-fail",
+}
+
+// migration - an upgrade of document, restructure enables changes of layout affecting execution, like splitting
+// scripts into steps, they are done by config migrate only, while loaded files are upgraded without them.
+type migration struct {
+	from, to string
+	apply    func(doc yaml.MapSlice, restructure bool, warn func(format string, args ...interface{}))
+}
+
+// migrations - a chain of configuration layout upgrades, every migration upgrades document from one version to next one.
+var migrations = []migration{
+	{from: "1.0", to: "1.1", apply: migrateV11},
+}
+
+// MigrateContent - upgrade configuration file content to CurrentVersion, return deprecation warnings for every
+// change done. Content is returned unchanged if it is already at CurrentVersion. Layout changes affecting execution
+// are done only with restructure, otherwise they are just reported.
+func MigrateContent(content []byte, restructure bool) ([]byte, []string, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, nil, err
+	}
+	version, warnings, err := Migrate(&doc, restructure)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version == CurrentVersion && len(warnings) == 0 {
+		return content, nil, nil
+	}
+	result, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append([]byte("---\n"), result...), warnings, nil
+}
+
+// Migrate - upgrade configuration document to CurrentVersion in place, return original document version and
+// deprecation warnings for every change done, see MigrateContent for restructure.
+func Migrate(doc *yaml.MapSlice, restructure bool) (string, []string, error) {
+	version := defaultVersion
+	versionIdx := -1
+	for idx, item := range *doc {
+		if item.Key == "version" {
+			version = versionString(item.Value)
+			versionIdx = idx
+		}
+	}
+	supported := false
+	for _, v := range SupportedVersions {
+		supported = supported || v == version
+	}
+	if !supported {
+		return "", nil, errors.Errorf("unsupported configuration version %q, supported versions are %s",
+			version, strings.Join(SupportedVersions, ", "))
+	}
+
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	current := version
+	for _, m := range migrations {
+		if m.from == current {
+			m.apply(*doc, restructure, warn)
+			current = m.to
+		}
+	}
+	if current != version {
+		if versionIdx == -1 {
+			*doc = append(yaml.MapSlice{{Key: "version", Value: current}}, *doc...)
+		} else {
+			(*doc)[versionIdx].Value = current
+		}
+	}
+	return version, warnings, nil
+}
+
+func versionString(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		result := strconv.FormatFloat(f, 'f', -1, 64)
+		if !strings.Contains(result, ".") {
+			result += ".0"
+		}
+		return result
+	}
+	if i, ok := value.(int); ok {
+		return fmt.Sprintf("%d.0", i)
+	}
+	return fmt.Sprint(value)
+}
+
+// migrateV11 - rename on_fail into on-fail, replace integer number of seconds with duration strings, doubled for
+// timeouts of executions, and split multi-line scripts of providers into steps on restructure.
+func migrateV11(doc yaml.MapSlice, restructure bool, warn func(format string, args ...interface{})) {
+	// Integer durations are accepted as is, so all of them are reported with one warning.
+	var integers []string
+	durations := func(item yaml.MapSlice, path string, keys ...string) {
+		for idx := range item {
+			for _, key := range keys {
+				if seconds, ok := item[idx].Value.(int); ok && item[idx].Key == key {
+					value := Duration(time.Duration(seconds) * time.Second).String()
+					integers = append(integers, fmt.Sprintf("%s%s: %s", path, key, value))
+					item[idx].Value = value
+				}
+			}
+		}
+	}
+
+	durations(doc, "", "timeout")
+	lineByLine := lineByLineProviders(doc)
+	forEachItem(doc, "providers", func(item yaml.MapSlice, path string) {
+		durations(item, path, "timeout", "stop-delay", "test-delay")
+		if lineByLine[itemName(item)] {
+			splitScripts(item, path, restructure, warn)
+		}
+	})
+	forEachItem(doc, "executions", func(item yaml.MapSlice, path string) {
+		// Integer timeout of execution was doubled for every test, so the doubled value is kept.
+		for idx := range item {
+			if seconds, ok := item[idx].Value.(int); ok && item[idx].Key == "timeout" {
+				value := Duration(2 * time.Duration(seconds) * time.Second).String()
+				warn("%stimeout: execution timeout is no longer doubled for tests, %d seconds are migrated to %s",
+					path, seconds, value)
+				item[idx].Value = value
+			}
+		}
+		for idx := range item {
+			if name, ok := RenamedFields[fmt.Sprint(item[idx].Key)]; ok {
+				warn("%s%s is deprecated, use %s", path, item[idx].Key, name)
+				item[idx].Key = name
+			}
+		}
+	})
+	forEachItem(doc, "health-check", func(item yaml.MapSlice, path string) {
+		durations(item, path, "interval")
+	})
+	for _, item := range doc {
+		if section, ok := item.Value.(yaml.MapSlice); ok {
+			switch item.Key {
+			case "retest":
+				durations(section, "retest.", "warmup-time")
+			case "statistics":
+				durations(section, "statistics.", "interval")
+			}
+		}
+	}
+	if len(integers) > 0 {
+		warn("integer numbers of seconds are deprecated, use duration strings: %s", strings.Join(integers, ", "))
+	}
+}
+
+// lineByLineProviders - return names of providers executing scripts line by line, a provider is executed line by line
+// if neither it nor any of providers extending it directly or through others sets a shell.
+func lineByLineProviders(doc yaml.MapSlice) map[string]bool {
+	parents := map[string]string{}
+	withShell := map[string]bool{}
+	result := map[string]bool{}
+	forEachItem(doc, "providers", func(item yaml.MapSlice, path string) {
+		name := itemName(item)
+		result[name] = true
+		for _, field := range item {
+			switch field.Key {
+			case "extends":
+				parents[name] = fmt.Sprint(field.Value)
+			case "shell":
+				withShell[name] = fmt.Sprint(field.Value) != ""
+			}
+		}
+	})
+	for name := range withShell {
+		if !withShell[name] {
+			continue
+		}
+		visited := map[string]bool{}
+		for p := name; p != "" && !visited[p]; p = parents[p] {
+			visited[p] = true
+			result[p] = false
+		}
+	}
+	return result
+}
+
+// splitScripts - replace every multi-line script of provider with a list of steps, one step for every line. Without
+// restructure scripts are kept as is and run as one step, only a warning is reported.
+func splitScripts(item yaml.MapSlice, path string, restructure bool, warn func(format string, args ...interface{})) {
+	for _, field := range item {
+		scripts, ok := field.Value.(yaml.MapSlice)
+		if field.Key != "scripts" || !ok {
+			continue
+		}
+		for idx := range scripts {
+			script, ok := scripts[idx].Value.(string)
+			if !ok {
+				continue
+			}
+			var steps []interface{}
+			for _, line := range strings.Split(script, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					steps = append(steps, yaml.MapSlice{{Key: "run", Value: line}})
+				}
+			}
+			if len(steps) < 2 {
+				continue
+			}
+			if !restructure {
+				warn("%sscripts.%s: multi-line script is deprecated, use a list of steps", path, scripts[idx].Key)
+				continue
+			}
+			warn("%sscripts.%s: multi-line script is replaced with a list of steps", path, scripts[idx].Key)
+			scripts[idx].Value = steps
+		}
+	}
+}
+
+// itemName - return a name of list section item.
+func itemName(item yaml.MapSlice) string {
+	for _, field := range item {
+		if field.Key == "name" {
+			return fmt.Sprint(field.Value)
+		}
+	}
+	return ""
+}
+
+// forEachItem - call fn for every item of list section, path is a section and item name or index with trailing dot.
+func forEachItem(doc yaml.MapSlice, section string, fn func(item yaml.MapSlice, path string)) {
+	for _, s := range doc {
+		items, ok := s.Value.([]interface{})
+		if s.Key != section || !ok {
+			continue
+		}
+		for idx, i := range items {
+			item, ok := i.(yaml.MapSlice)
+			if !ok {
+				continue
+			}
+			name := itemName(item)
+			if name == "" {
+				name = strconv.Itoa(idx)
+			}
+			fn(item, fmt.Sprintf("%s.%s.", section, name))
+		}
+	}
+}
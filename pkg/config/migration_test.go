@@ -0,0 +1,143 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+const legacyConfig = `---
+version: 1.0
+timeout: 7200
+providers:
+  - name: packet
+    timeout: 300
+    stop-delay: 10
+    scripts:
+      config: echo ./config
+      start: |
+        make packet-start
+
+        make packet-wait
+  - name: base
+    scripts:
+      start: |
+        cd ./deployments
+        ./start.sh
+  - name: bash
+    extends: base
+    shell: bash
+executions:
+  - name: basic
+    timeout: 60
+    on_fail: echo failed
+health-check:
+  - interval: 30
+retest:
+  warmup-time: 15
+`
+
+// migratedConfig - legacyConfig migrated to current version.
+const migratedConfig = `---
+version: "1.1"
+timeout: 2h0m0s
+providers:
+- name: packet
+  timeout: 5m0s
+  stop-delay: 10s
+  scripts:
+    config: echo ./config
+    start:
+    - run: make packet-start
+    - run: make packet-wait
+- name: base
+  scripts:
+    start: |
+      cd ./deployments
+      ./start.sh
+- name: bash
+  extends: base
+  shell: bash
+executions:
+- name: basic
+  timeout: 2m0s
+  on-fail: echo failed
+health-check:
+- interval: 30s
+retest:
+  warmup-time: 15s
+`
+
+func TestMigrateLegacyConfig(t *testing.T) {
+	g := gomega.NewWithT(t)
+	content, warnings, err := MigrateContent([]byte(legacyConfig), true)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(warnings).Should(gomega.Equal([]string{
+		"providers.packet.scripts.start: multi-line script is replaced with a list of steps",
+		"executions.basic.timeout: execution timeout is no longer doubled for tests, 60 seconds are migrated to 2m0s",
+		"executions.basic.on_fail is deprecated, use on-fail",
+		"integer numbers of seconds are deprecated, use duration strings: timeout: 2h0m0s, providers.packet.timeout: 5m0s, " +
+			"providers.packet.stop-delay: 10s, health-check.0.interval: 30s, retest.warmup-time: 15s",
+	}))
+	g.Expect(string(content)).Should(gomega.Equal(migratedConfig))
+
+	cfg := NewCloudTestConfig()
+	g.Expect(yaml.UnmarshalStrict(content, cfg)).Should(gomega.BeNil())
+	g.Expect(cfg.Version).Should(gomega.Equal(CurrentVersion))
+	g.Expect(cfg.Timeout.Duration()).Should(gomega.Equal(2 * time.Hour))
+	g.Expect(cfg.Executions[0].OnFail.String()).Should(gomega.Equal("echo failed"))
+	g.Expect(cfg.Providers[0].Scripts["start"]).Should(gomega.Equal(Script{{Run: "make packet-start"}, {Run: "make packet-wait"}}))
+	g.Expect(cfg.Executions[0].Timeout.Duration()).Should(gomega.Equal(2 * time.Minute))
+
+	again, warnings, err := MigrateContent(content, true)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(warnings).Should(gomega.BeEmpty())
+	g.Expect(again).Should(gomega.Equal(content))
+}
+
+func TestMigrateKeepsScriptsOnLoad(t *testing.T) {
+	g := gomega.NewWithT(t)
+	content, warnings, err := MigrateContent([]byte(legacyConfig), false)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(warnings).Should(gomega.ContainElement(
+		"providers.packet.scripts.start: multi-line script is deprecated, use a list of steps"))
+
+	cfg := NewCloudTestConfig()
+	g.Expect(yaml.UnmarshalStrict(content, cfg)).Should(gomega.BeNil())
+	g.Expect(cfg.Providers[0].Scripts["start"].IsPlain()).Should(gomega.BeTrue())
+	g.Expect(cfg.Providers[0].Scripts["start"].String()).Should(gomega.Equal("make packet-start\n\nmake packet-wait\n"))
+	g.Expect(cfg.Executions[0].OnFail.String()).Should(gomega.Equal("echo failed"))
+	g.Expect(cfg.Executions[0].Timeout.Duration()).Should(gomega.Equal(2 * time.Minute))
+}
+
+func TestMigrateStampsVersion(t *testing.T) {
+	g := gomega.NewWithT(t)
+	content, warnings, err := MigrateContent([]byte("executions:\n  - name: basic\n"), false)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(warnings).Should(gomega.BeEmpty())
+	g.Expect(string(content)).Should(gomega.Equal("---\nversion: \"1.1\"\nexecutions:\n- name: basic\n"))
+}
+
+func TestMigrateUnsupportedVersion(t *testing.T) {
+	g := gomega.NewWithT(t)
+	_, _, err := MigrateContent([]byte("version: 2.0\n"), false)
+	g.Expect(err).Should(gomega.MatchError(`unsupported configuration version "2.0", supported versions are 1.0, 1.1`))
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Profile - a named configuration overlay, keys are configuration paths like "executions.basic.timeout"
+// or nested sections, every value is set using SetValue.
+type Profile map[string]interface{}
+
+// Values - return a flat map of configuration path to yaml encoded value.
+func (p Profile) Values() (map[string]string, error) {
+	result := map[string]string{}
+	var flatten func(prefix string, value interface{}) error
+	flatten = func(prefix string, value interface{}) error {
+		switch v := value.(type) {
+		case map[interface{}]interface{}:
+			for key, item := range v {
+				if err := flatten(prefix+"."+fmt.Sprint(key), item); err != nil {
+					return err
+				}
+			}
+		case Profile:
+			for key, item := range v {
+				if err := flatten(prefix+"."+key, item); err != nil {
+					return err
+				}
+			}
+		default:
+			out, err := yaml.Marshal(v)
+			if err != nil {
+				return err
+			}
+			result[strings.TrimPrefix(prefix, ".")] = string(out)
+		}
+		return nil
+	}
+	if err := flatten("", p); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ApplyProfile - set all values defined by profile with passed name.
+func (c *CloudTestConfig) ApplyProfile(name string) error {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		var names []string
+		for n := range c.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return errors.Errorf("profile %q is not defined, available profiles: %v", name, names)
+	}
+	values, err := profile.Values()
+	if err != nil {
+		return errors.Wrapf(err, "invalid profile %q", name)
+	}
+	var paths []string
+	for p := range values {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if err := SetValue(c, p, values[p]); err != nil {
+			return errors.Wrapf(err, "profile %q", name)
+		}
+	}
+	return nil
+}
+
+// SetValue - set configuration value by dotted path of yaml field names, like "executions.basic.timeout".
+// List items are selected by name or by index, map values by key. Value is parsed as yaml.
+func SetValue(target interface{}, path, value string) error {
+	v := reflect.ValueOf(target)
+	segments := strings.Split(path, ".")
+	for idx, name := range segments {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			field, ok := fieldByYamlName(v, name)
+			if !ok {
+				return errors.Errorf("%s: unknown field %q", path, name)
+			}
+			v = field
+		case reflect.Slice:
+			item, ok := itemByName(v, name)
+			if !ok {
+				return errors.Errorf("%s: no item with name or index %q", path, name)
+			}
+			v = item
+		case reflect.Map:
+			if idx != len(segments)-1 {
+				return errors.Errorf("%s: only a value of %q could be set", path, strings.Join(segments[:idx+1], "."))
+			}
+			item := reflect.New(v.Type().Elem())
+			if err := yaml.UnmarshalStrict([]byte(value), item.Interface()); err != nil {
+				return errors.Wrapf(err, "%s: invalid value %q", path, value)
+			}
+			if v.IsNil() {
+				v.Set(reflect.MakeMap(v.Type()))
+			}
+			v.SetMapIndex(reflect.ValueOf(name).Convert(v.Type().Key()), item.Elem())
+			return nil
+		default:
+			return errors.Errorf("%s: %q is not a section", path, strings.Join(segments[:idx], "."))
+		}
+	}
+	result := reflect.New(v.Type())
+	if err := yaml.UnmarshalStrict([]byte(value), result.Interface()); err != nil {
+		return errors.Wrapf(err, "%s: invalid value %q", path, value)
+	}
+	v.Set(result.Elem())
+	return nil
+}
+
+func fieldByYamlName(v reflect.Value, name string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		if strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0] == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func itemByName(v reflect.Value, name string) (reflect.Value, bool) {
+	for i := 0; i < v.Len(); i++ {
+		item := reflect.Indirect(v.Index(i))
+		if item.Kind() != reflect.Struct {
+			continue
+		}
+		if field := item.FieldByName("Name"); field.IsValid() && field.Kind() == reflect.String && field.String() == name {
+			return v.Index(i), true
+		}
+	}
+	if idx, err := strconv.Atoi(name); err == nil && idx >= 0 && idx < v.Len() {
+		return v.Index(idx), true
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+const profilesConfig = `---
+timeout: 1h
+providers:
+  - name: packet
+    instances: 1
+executions:
+  - name: basic
+    timeout: 60
+  - name: other
+profiles:
+  nightly:
+    timeout: 4h
+    executions:
+      basic:
+        timeout: 10m
+    providers.packet.instances: 4
+`
+
+func loadProfilesConfig(t *testing.T) *CloudTestConfig {
+	g := gomega.NewWithT(t)
+	cfg := NewCloudTestConfig()
+	g.Expect(yaml.UnmarshalStrict([]byte(profilesConfig), cfg)).Should(gomega.BeNil())
+	return cfg
+}
+
+func TestSetValue(t *testing.T) {
+	g := gomega.NewWithT(t)
+	cfg := loadProfilesConfig(t)
+
+	g.Expect(SetValue(cfg, "executions.basic.timeout", "600")).Should(gomega.BeNil())
+	g.Expect(SetValue(cfg, "executions.1.cluster-selector", "[packet]")).Should(gomega.BeNil())
+	g.Expect(SetValue(cfg, "providers.packet.instances", "4")).Should(gomega.BeNil())
+	g.Expect(SetValue(cfg, "providers.packet.parameters.key", "value")).Should(gomega.BeNil())
+	g.Expect(SetValue(cfg, "providers.packet.packet.preferred-facility", "sjc1")).Should(gomega.BeNil())
+	g.Expect(SetValue(cfg, "statistics.enabled", "false")).Should(gomega.BeNil())
+
+	g.Expect(cfg.Executions[0].Timeout.Duration()).Should(gomega.Equal(10 * time.Minute))
+	g.Expect(cfg.Executions[1].ClusterSelector).Should(gomega.Equal([]string{"packet"}))
+	g.Expect(cfg.Providers[0].Instances).Should(gomega.Equal(4))
+	g.Expect(cfg.Providers[0].Parameters).Should(gomega.Equal(map[string]string{"key": "value"}))
+	g.Expect(cfg.Providers[0].Packet.PreferredFacility).Should(gomega.Equal("sjc1"))
+	g.Expect(cfg.Statistics.Enabled).Should(gomega.BeFalse())
+}
+
+func TestSetValueErrors(t *testing.T) {
+	g := gomega.NewWithT(t)
+	cfg := loadProfilesConfig(t)
+
+	g.Expect(SetValue(cfg, "executions.missing.timeout", "600")).Should(gomega.MatchError(
+		`executions.missing.timeout: no item with name or index "missing"`))
+	g.Expect(SetValue(cfg, "executions.basic.unknown", "600")).Should(gomega.MatchError(
+		`executions.basic.unknown: unknown field "unknown"`))
+	g.Expect(SetValue(cfg, "timeout.value", "600")).Should(gomega.MatchError(
+		`timeout.value: "timeout" is not a section`))
+	g.Expect(SetValue(cfg, "providers.packet.instances", "many")).ShouldNot(gomega.BeNil())
+}
+
+func TestApplyProfile(t *testing.T) {
+	g := gomega.NewWithT(t)
+	cfg := loadProfilesConfig(t)
+
+	g.Expect(cfg.ApplyProfile("nightly")).Should(gomega.BeNil())
+	g.Expect(cfg.Timeout.Duration()).Should(gomega.Equal(4 * time.Hour))
+	g.Expect(cfg.Executions[0].Timeout.Duration()).Should(gomega.Equal(10 * time.Minute))
+	g.Expect(cfg.Providers[0].Instances).Should(gomega.Equal(4))
+
+	g.Expect(cfg.ApplyProfile("pr")).Should(gomega.MatchError(`profile "pr" is not defined, available profiles: [nightly]`))
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+//go:generate go run ./schemagen -descriptions descriptions.go -schema ../../docs/cloudtest.schema.json
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fieldEnums - allowed values of fields, keyed same as field descriptions.
+var fieldEnums = map[string][]string{
+	"ClusterProviderConfig.Kind": {"packet", "shell"},
+	"Execution.Kind":             {"gotest", "shell"},
+}
+
+var (
+	durationType   = reflect.TypeOf(Duration(0))
+	stringListType = reflect.TypeOf(StringList{})
+	scriptType     = reflect.TypeOf(Script{})
+	envFilesType   = reflect.TypeOf(EnvFiles{})
+	configType     = reflect.TypeOf(CloudTestConfig{})
+)
+
+// Schema - return JSON schema of configuration file.
+func Schema() ([]byte, error) {
+	return GenerateSchema(fieldDescriptions)
+}
+
+// GenerateSchema - generate JSON schema of configuration file, field names are taken from yaml tags,
+// descriptions are keyed by "Type.Field", like "Execution.Timeout", see ParseFieldDescriptions.
+func GenerateSchema(descriptions map[string]string) ([]byte, error) {
+	g := &schemaGenerator{
+		descriptions: descriptions,
+		definitions:  map[string]interface{}{},
+	}
+	schema := g.typeSchema(configType, configType.Name())
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "CloudTest configuration"
+	schema["definitions"] = g.definitions
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+type schemaGenerator struct {
+	descriptions map[string]string
+	definitions  map[string]interface{}
+}
+
+// typeSchema - return schema of type, key is a description key prefix of anonymous struct fields.
+func (g *schemaGenerator) typeSchema(t reflect.Type, key string) map[string]interface{} {
+	if t == durationType {
+		return map[string]interface{}{
+			"type":    []string{"string", "integer"},
+			"pattern": `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$|^[0-9]+$`,
+		}
+	}
+	if t == stringListType {
+		return map[string]interface{}{
+			"type":  []string{"string", "array"},
+			"items": map[string]interface{}{"type": "string"},
+		}
+	}
+	if t == scriptType {
+		return map[string]interface{}{
+			"oneOf": []interface{}{
+				map[string]interface{}{"type": "string"},
+				map[string]interface{}{"type": "array", "items": g.typeSchema(t.Elem(), key)},
+			},
+		}
+	}
+	if t == envFilesType {
+		item := map[string]interface{}{
+			"oneOf": []interface{}{
+				map[string]interface{}{"type": "string"},
+				g.typeSchema(t.Elem(), key),
+			},
+		}
+		return map[string]interface{}{
+			"oneOf": []interface{}{
+				map[string]interface{}{"type": "string"},
+				map[string]interface{}{"type": "array", "items": item},
+			},
+		}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.typeSchema(t.Elem(), key)
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": g.typeSchema(t.Elem(), key)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.typeSchema(t.Elem(), key)}
+	case reflect.Struct:
+		if t.Name() == "" || t == configType {
+			return g.structSchema(t, key)
+		}
+		if _, ok := g.definitions[t.Name()]; !ok {
+			// Register definition before processing of fields to support recursive types.
+			g.definitions[t.Name()] = map[string]interface{}{}
+			g.definitions[t.Name()] = g.structSchema(t, t.Name())
+		}
+		return map[string]interface{}{"$ref": "#/definitions/" + t.Name()}
+	}
+	return map[string]interface{}{}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type, key string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fieldKey := key + "." + field.Name
+		schema := g.typeSchema(field.Type, fieldKey)
+		if ref, ok := schema["$ref"]; ok {
+			// Description could not be placed next to $ref
+			schema = map[string]interface{}{"allOf": []interface{}{map[string]interface{}{"$ref": ref}}}
+		}
+		if description, ok := g.descriptions[fieldKey]; ok {
+			schema["description"] = description
+		}
+		if enum, ok := fieldEnums[fieldKey]; ok {
+			schema["enum"] = enum
+		}
+		if fieldKey == "CloudTestConfig.Version" {
+			// Version is usually written as a number, like 1.0
+			schema["type"] = []string{"string", "number"}
+			var enum []interface{}
+			for _, v := range SupportedVersions {
+				f, _ := strconv.ParseFloat(v, 64)
+				enum = append(enum, v, f)
+			}
+			schema["enum"] = enum
+		}
+		properties[name] = schema
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// ParseFieldDescriptions - parse comments of struct fields declared in Go files of dir, keyed by "Type.Field",
+// fields of anonymous structs are keyed by "Type.Field.Field".
+func ParseFieldDescriptions(dir string) (map[string]string, error) {
+	fileSet := token.NewFileSet()
+	packages, err := parser.ParseDir(fileSet, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	var parseStruct func(key string, st *ast.StructType)
+	parseStruct = func(key string, st *ast.StructType) {
+		for _, field := range st.Fields.List {
+			comment := field.Comment
+			if comment == nil {
+				comment = field.Doc
+			}
+			for _, name := range field.Names {
+				fieldKey := key + "." + name.Name
+				if comment != nil {
+					result[fieldKey] = strings.TrimSpace(comment.Text())
+				}
+				if nested, ok := field.Type.(*ast.StructType); ok {
+					parseStruct(fieldKey, nested)
+				}
+			}
+		}
+	}
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(node ast.Node) bool {
+				if spec, ok := node.(*ast.TypeSpec); ok {
+					if st, ok := spec.Type.(*ast.StructType); ok {
+						parseStruct(spec.Name.Name, st)
+					}
+				}
+				return true
+			})
+		}
+	}
+	return result, nil
+}
+
+// GenerateDescriptionsSource - generate Go source of field descriptions used by Schema.
+func GenerateDescriptionsSource(descriptions map[string]string) ([]byte, error) {
+	var keys []string
+	for key := range descriptions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	source := strings.Builder{}
+	_, _ = source.WriteString("// Code generated by schemagen. DO NOT EDIT.\n\npackage config\n\n")
+	_, _ = source.WriteString("// fieldDescriptions - comments of configuration struct fields, used as JSON schema descriptions.\n")
+	_, _ = source.WriteString("var fieldDescriptions = map[string]string{\n")
+	for _, key := range keys {
+		_, _ = source.WriteString(fmt.Sprintf("\t%q: %q,\n", key, descriptions[key]))
+	}
+	_, _ = source.WriteString("}\n")
+	return format.Source([]byte(source.String()))
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+const schemaFile = "../../docs/cloudtest.schema.json"
+
+func TestSchemaIsGenerated(t *testing.T) {
+	g := gomega.NewWithT(t)
+	descriptions, err := ParseFieldDescriptions(".")
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(fieldDescriptions).Should(gomega.Equal(descriptions), "field descriptions are outdated, run go generate ./...")
+
+	schema, err := Schema()
+	g.Expect(err).Should(gomega.BeNil())
+	content, err := ioutil.ReadFile(schemaFile)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(string(content)).Should(gomega.Equal(string(schema)), "%s is outdated, run go generate ./...", schemaFile)
+}
+
+func TestSchemaContent(t *testing.T) {
+	g := gomega.NewWithT(t)
+	content, err := Schema()
+	g.Expect(err).Should(gomega.BeNil())
+
+	var schema struct {
+		Properties  map[string]map[string]interface{} `json:"properties"`
+		Definitions map[string]struct {
+			Properties map[string]map[string]interface{} `json:"properties"`
+		} `json:"definitions"`
+	}
+	g.Expect(json.Unmarshal(content, &schema)).Should(gomega.BeNil())
+	g.Expect(schema.Properties["timeout"]["description"]).Should(gomega.Equal("Global timeout"))
+	g.Expect(schema.Properties["executions"]["items"]).Should(gomega.Equal(map[string]interface{}{"$ref": "#/definitions/Execution"}))
+
+	execution := schema.Definitions["Execution"].Properties
+	g.Expect(execution["kind"]["enum"]).Should(gomega.ConsistOf("gotest", "shell"))
+	g.Expect(execution["timeout"]["type"]).Should(gomega.ConsistOf("string", "integer"))
+	g.Expect(schema.Definitions["RetestConfig"].Properties["fail-result"]).ShouldNot(gomega.HaveKey("enum"))
+}
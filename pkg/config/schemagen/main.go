@@ -0,0 +1,61 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Schemagen - generates field descriptions of configuration structs and JSON schema of configuration file.
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+)
+
+func main() {
+	descriptionsFile := flag.String("descriptions", "descriptions.go", "A Go file to write field descriptions to")
+	schemaFile := flag.String("schema", "", "A JSON schema file to write")
+	flag.Parse()
+
+	descriptions, err := config.ParseFieldDescriptions(".")
+	if err != nil {
+		logrus.Errorf("failed to parse field descriptions: %v", err)
+		os.Exit(1)
+	}
+	source, err := config.GenerateDescriptionsSource(descriptions)
+	if err != nil {
+		logrus.Errorf("failed to generate field descriptions: %v", err)
+		os.Exit(1)
+	}
+	if err = ioutil.WriteFile(*descriptionsFile, source, 0644); err != nil {
+		logrus.Errorf("failed to write %s: %v", *descriptionsFile, err)
+		os.Exit(1)
+	}
+	if *schemaFile == "" {
+		return
+	}
+	schema, err := config.GenerateSchema(descriptions)
+	if err != nil {
+		logrus.Errorf("failed to generate schema: %v", err)
+		os.Exit(1)
+	}
+	if err = ioutil.WriteFile(*schemaFile, schema, 0644); err != nil {
+		logrus.Errorf("failed to write %s: %v", *schemaFile, err)
+		os.Exit(1)
+	}
+}
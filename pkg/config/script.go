@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var outputNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Script - a script, accepts a multi line string as well as a list of steps.
+type Script []*ScriptStep
+
+// ScriptStep - a step of script, steps are executed one by one.
+type ScriptStep struct {
+	Name            string   `yaml:"name"`              // A step name, used in output and as a name of step log file.
+	Run             string   `yaml:"run"`               // A multi line script of step.
+	Timeout         Duration `yaml:"timeout"`           // A timeout of step, step is failed if not completed in time.
+	Retries         int      `yaml:"retries"`           // A number of attempts to run failed step again.
+	RetryDelay      Duration `yaml:"retry-delay"`       // A delay before every retry of failed step.
+	ContinueOnError bool     `yaml:"continue-on-error"` // Continue with next step if step is failed.
+	Env             []string `yaml:"env"`               // Extra environment variables of step.
+	Outputs         []string `yaml:"outputs"`           // Names of values captured from KEY=VALUE lines of step output or $CLOUDTEST_OUTPUT file.
+}
+
+// NewScript - create a script of one step, an empty script is returned for empty string.
+func NewScript(run string) Script {
+	if strings.TrimSpace(run) == "" {
+		return nil
+	}
+	return Script{{Run: run}}
+}
+
+// String - return scripts of all steps, one by one.
+func (s Script) String() string {
+	var runs []string
+	for _, step := range s {
+		runs = append(runs, step.Run)
+	}
+	return strings.Join(runs, "\n")
+}
+
+// IsPlain - check if script is defined as a string, not as a list of steps.
+func (s Script) IsPlain() bool {
+	return len(s) == 1 && s[0].Name == "" && s[0].Timeout == 0 && s[0].Retries == 0 && s[0].RetryDelay == 0 &&
+		!s[0].ContinueOnError && len(s[0].Env) == 0 && len(s[0].Outputs) == 0
+}
+
+// Validate - check every step has a script to run, a valid number of retries and a unique name.
+func (s Script) Validate() error {
+	names := map[string]bool{}
+	for idx, step := range s {
+		if strings.TrimSpace(step.Run) == "" {
+			return errors.Errorf("step #%d: run should be specified", idx+1)
+		}
+		if step.Retries < 0 {
+			return errors.Errorf("step #%d: retries should not be negative", idx+1)
+		}
+		if step.Name != "" && names[step.Name] {
+			return errors.Errorf("step #%d: duplicate step name %q", idx+1, step.Name)
+		}
+		names[step.Name] = true
+		for _, output := range step.Outputs {
+			if !outputNamePattern.MatchString(output) {
+				return errors.Errorf("step #%d: invalid output name %q", idx+1, output)
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalYAML - read script from a string or a list of steps.
+func (s *Script) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err == nil {
+		*s = NewScript(value)
+		return nil
+	}
+	var steps []*ScriptStep
+	if err := unmarshal(&steps); err != nil {
+		return err
+	}
+	*s = steps
+	return nil
+}
+
+// MarshalYAML - write script of one step without options as a string.
+func (s Script) MarshalYAML() (interface{}, error) {
+	if s.IsPlain() {
+		return s[0].Run, nil
+	}
+	return []*ScriptStep(s), nil
+}
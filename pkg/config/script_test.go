@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+func TestScriptUnmarshal(t *testing.T) {
+	g := gomega.NewWithT(t)
+	execution := &Execution{}
+	err := yaml.UnmarshalStrict([]byte(`
+before: |
+  echo one
+  echo two
+run:
+  - name: apply
+    run: kubectl apply -f deployment.yaml
+    timeout: 2m
+    retries: 3
+    retry-delay: 10s
+    env:
+      - NAMESPACE=test
+  - run: kubectl get pods
+    continue-on-error: true
+`), execution)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(execution.Before.IsPlain()).Should(gomega.BeTrue())
+	g.Expect(execution.Before.String()).Should(gomega.Equal("echo one\necho two\n"))
+	g.Expect(execution.After).Should(gomega.BeNil())
+	g.Expect(execution.Run).Should(gomega.Equal(Script{
+		{
+			Name:       "apply",
+			Run:        "kubectl apply -f deployment.yaml",
+			Timeout:    Duration(2 * time.Minute),
+			Retries:    3,
+			RetryDelay: Duration(10 * time.Second),
+			Env:        []string{"NAMESPACE=test"},
+		},
+		{Run: "kubectl get pods", ContinueOnError: true},
+	}))
+	g.Expect(execution.Run.IsPlain()).Should(gomega.BeFalse())
+	g.Expect(execution.Run.String()).Should(gomega.Equal("kubectl apply -f deployment.yaml\nkubectl get pods"))
+
+	err = yaml.UnmarshalStrict([]byte("run:\n  - name: apply\n    command: kubectl apply\n"), execution)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+}
+
+func TestScriptMarshal(t *testing.T) {
+	g := gomega.NewWithT(t)
+	out, err := yaml.Marshal(&Execution{
+		Before: NewScript("echo before"),
+		Run:    Script{{Name: "apply", Run: "kubectl apply", Retries: 2}},
+	})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(string(out)).Should(gomega.ContainSubstring("before: echo before\n"))
+	g.Expect(string(out)).Should(gomega.ContainSubstring("run:\n- name: apply\n  run: kubectl apply\n  timeout: 0s\n  retries: 2\n"))
+}
+
+func TestScriptValidate(t *testing.T) {
+	g := gomega.NewWithT(t)
+	g.Expect(NewScript("echo").Validate()).Should(gomega.BeNil())
+	g.Expect(Script{{Run: "echo"}, {Name: "empty"}}.Validate()).Should(gomega.MatchError("step #2: run should be specified"))
+	g.Expect(Script{{Run: "echo", Retries: -1}}.Validate()).Should(gomega.MatchError("step #1: retries should not be negative"))
+	g.Expect(Script{{Name: "a", Run: "echo"}, {Name: "a", Run: "echo"}}.Validate()).
+		Should(gomega.MatchError(`step #2: duplicate step name "a"`))
+	g.Expect(Script{{Run: "echo", Outputs: []string{"CLUSTER_NAME", "cluster-ip"}}}.Validate()).
+		Should(gomega.MatchError(`step #1: invalid output name "cluster-ip"`))
+}
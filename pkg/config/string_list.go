@@ -0,0 +1,43 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+// StringList - a list of strings, accepts a single string as well as a list.
+type StringList []string
+
+// UnmarshalYAML - read list from a single string or a list of strings.
+func (l *StringList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err == nil {
+		*l = StringList{value}
+		return nil
+	}
+	var values []string
+	if err := unmarshal(&values); err != nil {
+		return err
+	}
+	*l = values
+	return nil
+}
+
+// MarshalYAML - write list with a single item as a string.
+func (l StringList) MarshalYAML() (interface{}, error) {
+	if len(l) == 1 {
+		return l[0], nil
+	}
+	return []string(l), nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// applyTestAnnotations - read options of tests from magic comments of test functions of package files, like
+// "// cloudtest:timeout=10m cluster-count=2 providers=packet,gke retry=2", and set them to tests listed by name.
+func applyTestAnnotations(dir string, files []string, tests map[string]*TestEntry) error {
+	fileSet := token.NewFileSet()
+	for _, f := range files {
+		file, err := parser.ParseFile(fileSet, filepath.Join(dir, f), nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Doc == nil {
+				continue
+			}
+			t, ok := tests[fn.Name.Name]
+			if !ok {
+				continue
+			}
+			for _, comment := range fn.Doc.List {
+				line := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+				if !strings.HasPrefix(line, frontMatterPrefix) {
+					continue
+				}
+				for _, option := range strings.Fields(strings.TrimPrefix(line, frontMatterPrefix)) {
+					if strings.HasPrefix(option, "tags=") {
+						err = errors.New("tags of go tests are set by build constraints")
+					} else {
+						err = setTestOption(t, option)
+					}
+					if err != nil {
+						return errors.Wrapf(err, "%s: test %s", fileSet.Position(comment.Pos()), t.Name)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+func TestApplyTestAnnotations(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer utils.ClearFolder(tmpDir, false)
+
+	g.Expect(ioutil.WriteFile(filepath.Join(tmpDir, "a_test.go"), []byte(`package a
+
+import "testing"
+
+// TestA - annotated test.
+// cloudtest:timeout=10m cluster-count=2
+// cloudtest:providers=packet,gke retry=2
+func TestA(t *testing.T) {
+}
+
+func TestB(t *testing.T) {
+}
+`), 0600)).Should(gomega.BeNil())
+
+	tests := map[string]*TestEntry{
+		"TestA": {Name: "TestA"},
+		"TestB": {Name: "TestB"},
+	}
+	g.Expect(applyTestAnnotations(tmpDir, []string{"a_test.go"}, tests)).Should(gomega.BeNil())
+	g.Expect(tests["TestA"].Timeout).Should(gomega.Equal(10 * time.Minute))
+	g.Expect(tests["TestA"].ClusterCount).Should(gomega.Equal(2))
+	g.Expect(tests["TestA"].Providers).Should(gomega.Equal([]string{"packet", "gke"}))
+	g.Expect(tests["TestA"].RetestCount).Should(gomega.Equal(2))
+	g.Expect(tests["TestB"]).Should(gomega.Equal(&TestEntry{Name: "TestB"}))
+
+	g.Expect(ioutil.WriteFile(filepath.Join(tmpDir, "b_test.go"), []byte(`package a
+
+import "testing"
+
+// cloudtest:tags=slow
+func TestB(t *testing.T) {
+}
+`), 0600)).Should(gomega.BeNil())
+	err = applyTestAnnotations(tmpDir, []string{"b_test.go"}, tests)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+	g.Expect(err.Error()).Should(gomega.ContainSubstring("b_test.go:5:1: test TestB: tags of go tests are set by build constraints"))
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/execmanager"
+)
+
+// BuildFailure - go test package failed to compile.
+type BuildFailure struct {
+	Package string // Import path of go test package
+	Tags    string // A list of tags package is compiled with
+	Output  string // Compiler output
+}
+
+func (f *BuildFailure) Error() string {
+	return fmt.Sprintf("failed to compile tests of %s with tags %q", f.Package, f.Tags)
+}
+
+// TestBinaries - compiles every go test package once with go test -c and caches test binaries under execution
+// manager root, binaries are reused by test discovery and all test runs.
+type TestBinaries struct {
+	manager  execmanager.ExecutionManager
+	root     string
+	binaries map[string]*testBinary
+	sync.Mutex
+}
+
+type testBinary struct {
+	index   int
+	path    string
+	failure *BuildFailure
+	err     error // An error of preparing binary build, returned to all waiters.
+	done    chan struct{}
+}
+
+// NewTestBinaries - create go test binaries cache.
+func NewTestBinaries(manager execmanager.ExecutionManager) *TestBinaries {
+	return &TestBinaries{
+		manager:  manager,
+		binaries: map[string]*testBinary{},
+	}
+}
+
+// Build - return test binary of package compiled in root folder with passed tags, package is compiled only once.
+// In case of compilation error BuildFailure is returned.
+func (b *TestBinaries) Build(root, pkg, tags string) (string, error) {
+	key := strings.Join([]string{root, pkg, tags}, "|")
+	b.Lock()
+	binary, ok := b.binaries[key]
+	if !ok {
+		binary = &testBinary{index: len(b.binaries), done: make(chan struct{})}
+		b.binaries[key] = binary
+	}
+	b.Unlock()
+	if ok {
+		<-binary.done
+		if binary.failure != nil {
+			return "", binary.failure
+		}
+		if binary.err != nil {
+			return "", binary.err
+		}
+		return binary.path, nil
+	}
+	defer close(binary.done)
+
+	binariesRoot, err := b.getRoot()
+	if err != nil {
+		binary.err = err
+		return "", err
+	}
+	name := strings.NewReplacer("/", "_", ".", "_").Replace(pkg)
+	if tags != "" {
+		name += "-" + strings.ReplaceAll(tags, ",", "_")
+	}
+	binary.path = path.Join(binariesRoot, fmt.Sprintf("%s-%d.test", name, binary.index))
+
+	cmd := []string{"go", "test", "-c", "-o", binary.path}
+	if tags != "" {
+		cmd = append(cmd, "-tags", tags)
+	}
+	cmd = append(cmd, pkg)
+	proc := exec.CommandContext(context.Background(), cmd[0], cmd[1:]...)
+	proc.Dir = root
+	output, err := proc.CombinedOutput()
+	b.manager.AddLog("gotest", "build-"+filepath.Base(pkg), strings.Join(cmd, " ")+"\n"+string(output))
+	if err != nil {
+		logrus.Errorf("Failed to compile tests of %s: %v\n%s", pkg, err, output)
+		binary.failure = &BuildFailure{
+			Package: pkg,
+			Tags:    tags,
+			Output:  fmt.Sprintf("%s\n%s%v", strings.Join(cmd, " "), output, err),
+		}
+		return "", binary.failure
+	}
+	return binary.path, nil
+}
+
+func (b *TestBinaries) getRoot() (string, error) {
+	b.Lock()
+	defer b.Unlock()
+	if b.root == "" {
+		root, err := b.manager.GetRoot("gotest-binaries")
+		if err != nil {
+			return "", err
+		}
+		b.root = root
+	}
+	return b.root, nil
+}
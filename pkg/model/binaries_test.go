@@ -0,0 +1,53 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/execmanager"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+func TestBinariesAreBuiltOnce(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer utils.ClearFolder(tmpDir, false)
+
+	binaries := NewTestBinaries(execmanager.NewExecutionManager(tmpDir))
+	binary, err := binaries.Build("../tests", "./sample/broken", "")
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(utils.FileExists(binary)).Should(gomega.BeTrue())
+
+	again, err := binaries.Build("../tests", "./sample/broken", "")
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(again).Should(gomega.Equal(binary))
+
+	_, err = binaries.Build("../tests", "./sample/broken", "broken")
+	failure, ok := err.(*BuildFailure)
+	g.Expect(ok).Should(gomega.BeTrue())
+	g.Expect(failure.Package).Should(gomega.Equal("./sample/broken"))
+	g.Expect(failure.Output).Should(gomega.ContainSubstring("undefinedValue"))
+
+	_, err = binaries.Build("../tests", "./sample/broken", "broken")
+	g.Expect(err).Should(gomega.Equal(failure))
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// TestResult - a result of test or subtest reported by go test.
+type TestResult struct {
+	Name     string        // Test name, like TestParent/child
+	Status   Status        // One of StatusSuccess, StatusFailed or StatusSkippedByTest
+	Duration time.Duration // Test duration
+	Output   string        // Output of test only
+}
+
+// Result - return result of test itself reported by last execution, nil if it is not reported.
+func (t *TestEntry) Result() *TestResult {
+	for _, r := range t.Results {
+		if r.Name == t.Name {
+			return r
+		}
+	}
+	return nil
+}
+
+// SubtestResults - return results of direct subtests of name, in order they were started.
+func (t *TestEntry) SubtestResults(name string) []*TestResult {
+	var result []*TestResult
+	for _, r := range t.Results {
+		if strings.HasPrefix(r.Name, name+"/") && !strings.Contains(r.Name[len(name)+1:], "/") {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// SkipReason - return a message test is skipped with, go test progress lines are omitted.
+func (r *TestResult) SkipReason() string {
+	var lines []string
+	for _, line := range strings.Split(r.Output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "=== ") || strings.HasPrefix(trimmed, "--- ") {
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+	return strings.Join(lines, "\n")
+}
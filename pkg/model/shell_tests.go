@@ -0,0 +1,180 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+)
+
+// frontMatterPrefix - a prefix of script comments with test options, like "# cloudtest:timeout=10m cluster-count=2".
+const frontMatterPrefix = "cloudtest:"
+
+// GetShellTests - return a test for every script file found by patterns, a pattern could be a folder or a glob.
+// Options of test are read from front matter comments of script. If source tags are set, scripts selected by tag
+// expressions are returned, otherwise only scripts without tags.
+func GetShellTests(patterns []string, source config.ExecutionSource) ([]*TestEntry, error) {
+	var expr *TagExpression
+	if len(source.Tags) > 0 {
+		var err error
+		if expr, err = ParseTagExpressions(source.Tags); err != nil {
+			return nil, err
+		}
+	}
+	var result []*TestEntry
+	for _, pattern := range patterns {
+		files, err := findScripts(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			t, err := newShellTest(file)
+			if err != nil {
+				return nil, err
+			}
+			tags := []string{}
+			if t.Tags != "" {
+				tags = strings.Split(t.Tags, ",")
+			}
+			if expr == nil && len(tags) == 0 || expr != nil && expr.Eval(tags) {
+				result = append(result, t)
+			}
+		}
+	}
+	return result, nil
+}
+
+// findScripts - return sorted regular files of folder or files matching glob.
+func findScripts(pattern string) ([]string, error) {
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		pattern = filepath.Join(pattern, "*")
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid scripts pattern %q", pattern)
+	}
+	var files []string
+	for _, m := range matches {
+		if info, err := os.Stat(m); err == nil && info.Mode().IsRegular() {
+			files = append(files, m)
+		}
+	}
+	if len(files) == 0 {
+		return nil, errors.Errorf("no scripts found by %q", pattern)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// newShellTest - create a test of script file, script is executed directly if it is executable, or with bash.
+func newShellTest(file string) (*TestEntry, error) {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(absFile)
+	if err != nil {
+		return nil, err
+	}
+	t := &TestEntry{
+		Name: filepath.Clean(file),
+		Kind: TestEntryKindShellTest,
+	}
+	runScript := "\"" + absFile + "\""
+	if info.Mode()&0111 == 0 {
+		runScript = "bash " + runScript
+	}
+	t.RunScript = config.NewScript(runScript)
+	if err := readFrontMatter(absFile, t); err != nil {
+		return nil, errors.Wrapf(err, "script %s", file)
+	}
+	return t, nil
+}
+
+// readFrontMatter - read test options from leading comments of script, like "# cloudtest:timeout=10m cluster-count=2",
+// options could be split into few comments.
+func readFrontMatter(file string, t *TestEntry) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		if !strings.HasPrefix(line, frontMatterPrefix) {
+			continue
+		}
+		for _, option := range strings.Fields(strings.TrimPrefix(line, frontMatterPrefix)) {
+			if err := setTestOption(t, option); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// setTestOption - set test option passed as key=value.
+func setTestOption(t *TestEntry, option string) error {
+	kv := strings.SplitN(option, "=", 2)
+	if len(kv) != 2 {
+		return errors.Errorf("invalid option %q, key=value is expected", option)
+	}
+	key, value := kv[0], kv[1]
+	switch key {
+	case "timeout":
+		timeout, err := config.ParseDuration(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid timeout %q", value)
+		}
+		t.Timeout = timeout.Duration()
+	case "cluster-count":
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 1 {
+			return errors.Errorf("invalid cluster-count %q", value)
+		}
+		t.ClusterCount = count
+	case "tags":
+		t.Tags = value
+	case "providers":
+		t.Providers = strings.Split(value, ",")
+	case "retry":
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 1 {
+			return errors.Errorf("invalid retry %q", value)
+		}
+		t.RetestCount = count
+	default:
+		return errors.Errorf("unknown option %q", key)
+	}
+	return nil
+}
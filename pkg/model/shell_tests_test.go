@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+func TestShellTestsFrontMatter(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer utils.ClearFolder(tmpDir, false)
+
+	for name, content := range map[string]string{
+		"b.sh": "#!/bin/bash\n\n# cloudtest:timeout=10m\n# cloudtest:cluster-count=2 tags=slow,nightly\necho b\n# cloudtest:timeout=1m\n",
+		"a.sh": "echo a\n",
+	} {
+		g.Expect(ioutil.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600)).Should(gomega.BeNil())
+	}
+
+	tests, err := GetShellTests([]string{tmpDir}, config.ExecutionSource{})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(len(tests)).Should(gomega.Equal(1))
+	g.Expect(tests[0].Name).Should(gomega.Equal(filepath.Join(tmpDir, "a.sh")))
+	g.Expect(tests[0].RunScript.String()).Should(gomega.Equal("bash \"" + filepath.Join(tmpDir, "a.sh") + "\""))
+
+	tests, err = GetShellTests([]string{filepath.Join(tmpDir, "*.sh")}, config.ExecutionSource{Tags: []string{"slow && nightly"}})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(len(tests)).Should(gomega.Equal(1))
+	g.Expect(tests[0].Timeout).Should(gomega.Equal(10 * time.Minute))
+	g.Expect(tests[0].ClusterCount).Should(gomega.Equal(2))
+	g.Expect(tests[0].Tags).Should(gomega.Equal("slow,nightly"))
+
+	_, err = GetShellTests([]string{filepath.Join(tmpDir, "*.bash")}, config.ExecutionSource{})
+	g.Expect(err).ShouldNot(gomega.BeNil())
+
+	g.Expect(ioutil.WriteFile(filepath.Join(tmpDir, "c.sh"), []byte("# cloudtest:retries=2\n"), 0600)).Should(gomega.BeNil())
+	_, err = GetShellTests([]string{tmpDir}, config.ExecutionSource{})
+	g.Expect(err).ShouldNot(gomega.BeNil())
+	g.Expect(err.Error()).Should(gomega.ContainSubstring("unknown option \"retries\""))
+
+	g.Expect(ioutil.WriteFile(filepath.Join(tmpDir, "c.sh"), []byte("# cloudtest:timeout=90\n"), 0600)).Should(gomega.BeNil())
+	tests, err = GetShellTests([]string{filepath.Join(tmpDir, "c.sh")}, config.ExecutionSource{})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(len(tests)).Should(gomega.Equal(1))
+	g.Expect(tests[0].Timeout).Should(gomega.Equal(90 * time.Second))
+}
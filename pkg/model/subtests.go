@@ -0,0 +1,312 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/execmanager"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+// ExpandSubtests - replace every test with its subtests, keyed by qualified name. Subtests are found by parsing of
+// test sources: t.Run calls with string literal names and table driven t.Run calls ranging over a literal table are
+// supported. Tests with no subtests or with subtests could not be resolved are kept as is.
+func ExpandSubtests(manager execmanager.ExecutionManager, tests map[string]*TestEntry) (map[string]*TestEntry, error) {
+	// Package test files are listed once for every package.
+	files := map[string][]string{}
+	result := map[string]*TestEntry{}
+	for key, t := range tests {
+		if t.Package == "" {
+			result[key] = t
+			continue
+		}
+		pkgKey := t.PackageRoot + "|" + t.Package + "|" + t.Tags
+		if _, ok := files[pkgKey]; !ok {
+			pkgFiles, err := listTestFiles(manager, t)
+			if err != nil {
+				return nil, err
+			}
+			files[pkgKey] = pkgFiles
+		}
+		subtests, err := findSubtests(files[pkgKey], t.Name)
+		if err != nil {
+			return nil, err
+		}
+		if len(subtests) == 0 {
+			result[key] = t
+			continue
+		}
+		for _, name := range subtests {
+			sub := &TestEntry{
+				Name:         t.Name + "/" + name,
+				Package:      t.Package,
+				PackageRoot:  t.PackageRoot,
+				PackageDir:   t.PackageDir,
+				Binary:       t.Binary,
+				Tags:         t.Tags,
+				Timeout:      t.Timeout,
+				ClusterCount: t.ClusterCount,
+				Providers:    t.Providers,
+				RetestCount:  t.RetestCount,
+			}
+			result[sub.QualifiedName()] = sub
+		}
+	}
+	return result, nil
+}
+
+func listTestFiles(manager execmanager.ExecutionManager, t *TestEntry) ([]string, error) {
+	cmd := []string{"go", "list", "-f", "{{.Dir}}{{range .TestGoFiles}} {{.}}{{end}}{{range .XTestGoFiles}} {{.}}{{end}}"}
+	if t.Tags != "" {
+		cmd = append(cmd, "-tags", t.Tags)
+	}
+	cmd = append(cmd, t.Package)
+	output, err := utils.ExecRead(context.Background(), t.PackageRoot, cmd)
+	manager.AddLog("gotest", "find-subtests", strings.Join(cmd, " ")+"\n"+strings.Join(output, "\n"))
+	if err != nil || len(output) == 0 {
+		logrus.Errorf("Error getting list of test files: %v\nOutput: %v\nCmdLine: %v", err, output, cmd)
+		return nil, errors.Errorf("failed to list test files of %s", t.Package)
+	}
+	fields := strings.Fields(output[0])
+	var result []string
+	for _, f := range fields[1:] {
+		result = append(result, filepath.Join(fields[0], f))
+	}
+	return result, nil
+}
+
+// findSubtests - return names of first level subtests of test function, as they are reported by go test.
+// Nil is returned if test has no subtests or any of its subtest names could not be resolved.
+func findSubtests(files []string, testName string) ([]string, error) {
+	fileSet := token.NewFileSet()
+	for _, f := range files {
+		file, err := parser.ParseFile(fileSet, f, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Name.Name != testName || fn.Body == nil || len(fn.Type.Params.List) != 1 {
+				continue
+			}
+			param := fn.Type.Params.List[0]
+			if len(param.Names) != 1 {
+				return nil, nil
+			}
+			names, resolved := subtestNames(fn.Body, param.Names[0].Name)
+			if !resolved {
+				logrus.Infof("Subtests of %s could not be resolved, test is executed as a whole", testName)
+				return nil, nil
+			}
+			return uniqueSubtestNames(names), nil
+		}
+	}
+	return nil, nil
+}
+
+// subtestNames - find names of t.Run calls in function body, return false if some of names could not be resolved.
+func subtestNames(body *ast.BlockStmt, t string) ([]string, bool) {
+	var names []string
+	resolved := true
+	var stack []ast.Node
+	ast.Inspect(body, func(node ast.Node) bool {
+		if node == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		if _, ok := node.(*ast.FuncLit); ok {
+			// Nested subtests and helper closures are not processed.
+			return false
+		}
+		stack = append(stack, node)
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Run" {
+			return true
+		}
+		if recv, ok := sel.X.(*ast.Ident); !ok || recv.Name != t {
+			return true
+		}
+		values := resolveStrings(call.Args[0], stack)
+		if values == nil {
+			resolved = false
+		}
+		names = append(names, values...)
+		return true
+	})
+	return names, resolved
+}
+
+// resolveStrings - return all values of expression, it could be a string literal, a field of table item or a key of
+// map ranged over by one of enclosing range statements.
+func resolveStrings(expr ast.Expr, stack []ast.Node) []string {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if value, err := strconv.Unquote(e.Value); err == nil && e.Kind == token.STRING {
+			return []string{value}
+		}
+	case *ast.Ident:
+		if rng := enclosingRange(e.Name, stack, true); rng != nil {
+			return mapKeys(tableLiteral(rng.X))
+		}
+	case *ast.SelectorExpr:
+		item, ok := e.X.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		if rng := enclosingRange(item.Name, stack, false); rng != nil {
+			return tableFields(tableLiteral(rng.X), e.Sel.Name)
+		}
+	}
+	return nil
+}
+
+// enclosingRange - find a range statement declaring variable as its key or value.
+func enclosingRange(name string, stack []ast.Node, key bool) *ast.RangeStmt {
+	for idx := len(stack) - 1; idx >= 0; idx-- {
+		rng, ok := stack[idx].(*ast.RangeStmt)
+		if !ok {
+			continue
+		}
+		variable := rng.Value
+		if key {
+			variable = rng.Key
+		}
+		if ident, ok := variable.(*ast.Ident); ok && ident.Name == name {
+			return rng
+		}
+	}
+	return nil
+}
+
+// tableLiteral - return composite literal of ranged expression, a literal itself or a variable initialized by it.
+func tableLiteral(expr ast.Expr) *ast.CompositeLit {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return e
+	case *ast.Ident:
+		if e.Obj == nil {
+			return nil
+		}
+		switch decl := e.Obj.Decl.(type) {
+		case *ast.AssignStmt:
+			for idx, lhs := range decl.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == e.Name && idx < len(decl.Rhs) {
+					return tableLiteral(decl.Rhs[idx])
+				}
+			}
+		case *ast.ValueSpec:
+			for idx, ident := range decl.Names {
+				if ident.Name == e.Name && idx < len(decl.Values) {
+					return tableLiteral(decl.Values[idx])
+				}
+			}
+		}
+	case *ast.UnaryExpr:
+		return tableLiteral(e.X)
+	}
+	return nil
+}
+
+// tableFields - return string literal values of field of all table items.
+func tableFields(table *ast.CompositeLit, field string) []string {
+	if table == nil {
+		return nil
+	}
+	var result []string
+	for _, elt := range table.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			// Map of items
+			elt = kv.Value
+		}
+		item := tableLiteral(elt)
+		if item == nil {
+			return nil
+		}
+		found := false
+		for _, itemElt := range item.Elts {
+			kv, ok := itemElt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == field {
+				values := resolveStrings(kv.Value, nil)
+				if len(values) != 1 {
+					return nil
+				}
+				result = append(result, values[0])
+				found = true
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return result
+}
+
+// mapKeys - return string literal keys of map literal.
+func mapKeys(table *ast.CompositeLit) []string {
+	if table == nil {
+		return nil
+	}
+	var result []string
+	for _, elt := range table.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil
+		}
+		values := resolveStrings(kv.Key, nil)
+		if len(values) != 1 {
+			return nil
+		}
+		result = append(result, values[0])
+	}
+	return result
+}
+
+// uniqueSubtestNames - rewrite names same way go test does, spaces are replaced with underscores
+// and duplicate names get #01, #02 suffixes.
+func uniqueSubtestNames(names []string) []string {
+	seen := map[string]int{}
+	var result []string
+	for _, name := range names {
+		name = strings.ReplaceAll(name, " ", "_")
+		if count, ok := seen[name]; ok {
+			seen[name] = count + 1
+			name = fmt.Sprintf("%s#%02d", name, count+1)
+		} else {
+			seen[name] = 0
+		}
+		result = append(result, name)
+	}
+	return result
+}
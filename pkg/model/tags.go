@@ -0,0 +1,258 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// maxExpressionTags - a limit of tags in expression, tests are probed with every subset of expression tags.
+const maxExpressionTags = 6
+
+// TagExpression - a boolean expression of build tags, like "basic && !slow" or "(basic or nightly) and not slow".
+type TagExpression struct {
+	op   string // One of tag, not, and, or
+	tag  string
+	args []*TagExpression
+}
+
+// ParseTagExpressions - parse a list of tag expressions, test is selected if any of expressions is true.
+func ParseTagExpressions(expressions []string) (*TagExpression, error) {
+	result := &TagExpression{op: "or"}
+	for _, e := range expressions {
+		expr, err := ParseTagExpression(e)
+		if err != nil {
+			return nil, err
+		}
+		result.args = append(result.args, expr)
+	}
+	return result, nil
+}
+
+// ParseTagExpression - parse tag expression, "&&", "||", "!" and "and", "or", "not" operators and parentheses
+// are supported.
+func ParseTagExpression(expression string) (*TagExpression, error) {
+	p := &tagParser{tokens: tokenizeTags(expression)}
+	expr, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = errors.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid tag expression %q", expression)
+	}
+	return expr, nil
+}
+
+// Tags - return sorted unique tags of expression.
+func (e *TagExpression) Tags() []string {
+	set := map[string]bool{}
+	e.collectTags(set)
+	var result []string
+	for tag := range set {
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (e *TagExpression) collectTags(set map[string]bool) {
+	if e.op == "tag" {
+		set[e.tag] = true
+	}
+	for _, arg := range e.args {
+		arg.collectTags(set)
+	}
+}
+
+// Eval - evaluate expression with passed set of tags.
+func (e *TagExpression) Eval(tags []string) bool {
+	switch e.op {
+	case "tag":
+		for _, t := range tags {
+			if t == e.tag {
+				return true
+			}
+		}
+		return false
+	case "not":
+		return !e.args[0].Eval(tags)
+	case "and":
+		for _, arg := range e.args {
+			if !arg.Eval(tags) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, arg := range e.args {
+		if arg.Eval(tags) {
+			return true
+		}
+	}
+	return false
+}
+
+func tokenizeTags(expression string) []string {
+	var tokens []string
+	runes := []rune(expression)
+	for pos := 0; pos < len(runes); {
+		r := runes[pos]
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+		case r == '(' || r == ')' || r == '!':
+			tokens = append(tokens, string(r))
+			pos++
+		case (r == '&' || r == '|') && pos+1 < len(runes) && runes[pos+1] == r:
+			tokens = append(tokens, string(runes[pos:pos+2]))
+			pos += 2
+		default:
+			start := pos
+			for pos < len(runes) && isTagRune(runes[pos]) {
+				pos++
+			}
+			if pos == start {
+				// Unknown symbol, reported by parser.
+				pos++
+			}
+			tokens = append(tokens, string(runes[start:pos]))
+		}
+	}
+	return tokens
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
+
+type tagParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *tagParser) accept(tokens ...string) bool {
+	if p.pos >= len(p.tokens) {
+		return false
+	}
+	for _, t := range tokens {
+		if strings.EqualFold(p.tokens[p.pos], t) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *tagParser) parseOr() (*TagExpression, error) {
+	return p.parseBinary("or", []string{"||", "or"}, p.parseAnd)
+}
+
+func (p *tagParser) parseAnd() (*TagExpression, error) {
+	return p.parseBinary("and", []string{"&&", "and"}, p.parseUnary)
+}
+
+func (p *tagParser) parseBinary(op string, operators []string, parseArg func() (*TagExpression, error)) (*TagExpression, error) {
+	arg, err := parseArg()
+	if err != nil {
+		return nil, err
+	}
+	result := &TagExpression{op: op, args: []*TagExpression{arg}}
+	for p.accept(operators...) {
+		if arg, err = parseArg(); err != nil {
+			return nil, err
+		}
+		result.args = append(result.args, arg)
+	}
+	if len(result.args) == 1 {
+		return arg, nil
+	}
+	return result, nil
+}
+
+func (p *tagParser) parseUnary() (*TagExpression, error) {
+	if p.accept("!", "not") {
+		arg, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &TagExpression{op: "not", args: []*TagExpression{arg}}, nil
+	}
+	if p.accept("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, errors.New("missing )")
+		}
+		return expr, nil
+	}
+	if p.pos >= len(p.tokens) {
+		return nil, errors.New("unexpected end of expression")
+	}
+	tag := p.tokens[p.pos]
+	for _, r := range tag {
+		if !isTagRune(r) {
+			return nil, errors.Errorf("unexpected %q", tag)
+		}
+	}
+	switch strings.ToLower(tag) {
+	case "and", "or", "not":
+		return nil, errors.Errorf("unexpected %q", tag)
+	}
+	p.pos++
+	return &TagExpression{op: "tag", tag: tag}, nil
+}
+
+// tagSubsets - return all subsets of tags ordered by size, so every subset follows all its proper subsets.
+func tagSubsets(tags []string) [][]string {
+	var result [][]string
+	for mask := 0; mask < 1<<uint(len(tags)); mask++ {
+		var subset []string
+		for idx, tag := range tags {
+			if mask&(1<<uint(idx)) != 0 {
+				subset = append(subset, tag)
+			}
+		}
+		result = append(result, subset)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return len(result[i]) < len(result[j])
+	})
+	return result
+}
+
+// isProperSubset - check all of tags of a are in b and b has more tags.
+func isProperSubset(a, b []string) bool {
+	if len(a) >= len(b) {
+		return false
+	}
+	for _, tag := range a {
+		found := false
+		for _, t := range b {
+			found = found || t == tag
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
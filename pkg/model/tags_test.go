@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/execmanager"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+func TestTagExpressionEval(t *testing.T) {
+	g := gomega.NewWithT(t)
+	for expression, expected := range map[string][]bool{
+		// Results for {}, {basic}, {slow}, {basic, slow}
+		"basic":                    {false, true, false, true},
+		"basic && !slow":           {false, true, false, false},
+		"basic and not slow":       {false, true, false, false},
+		"basic || slow":            {false, true, true, true},
+		"!(basic or slow)":         {true, false, false, false},
+		"not basic && slow":        {false, false, true, false},
+		"basic && (slow || basic)": {false, true, false, true},
+	} {
+		expr, err := ParseTagExpression(expression)
+		g.Expect(err).Should(gomega.BeNil(), expression)
+		var result []bool
+		for _, tags := range [][]string{nil, {"basic"}, {"slow"}, {"basic", "slow"}} {
+			result = append(result, expr.Eval(tags))
+		}
+		g.Expect(result).Should(gomega.Equal(expected), expression)
+	}
+}
+
+func TestInvalidTagExpression(t *testing.T) {
+	g := gomega.NewWithT(t)
+	for _, expression := range []string{"", "basic &&", "(basic", "basic)", "basic slow", "basic & slow", "and"} {
+		_, err := ParseTagExpression(expression)
+		g.Expect(err).ShouldNot(gomega.BeNil(), expression)
+	}
+	expr, err := ParseTagExpressions([]string{"b || a", "!c && a"})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(expr.Tags()).Should(gomega.Equal([]string{"a", "b", "c"}))
+}
+
+func TestTaggedTestConfiguration(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer utils.ClearFolder(tmpDir, false)
+
+	binaries := NewTestBinaries(execmanager.NewExecutionManager(tmpDir))
+	for _, probe := range []struct {
+		tags     []string
+		expected map[string]string
+	}{
+		{[]string{"basic && !slow"}, map[string]string{"TestBasic": "basic"}},
+		{[]string{"basic && slow"}, map[string]string{"TestBasicSlow": "basic,slow"}},
+		{[]string{"nightly"}, map[string]string{"TestFastOrNightly": "nightly"}},
+		{[]string{"!basic"}, map[string]string{"TestUntagged": ""}},
+		{[]string{"slow", "fast"}, map[string]string{"TestFastOrNightly": "fast"}},
+	} {
+		tests, failures, err := GetTestConfiguration(binaries, []string{"../tests/sample/tags"}, config.ExecutionSource{Tags: probe.tags})
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(failures).Should(gomega.BeEmpty())
+		result := map[string]string{}
+		for _, test := range tests {
+			result[test.Name] = test.Tags
+		}
+		g.Expect(result).Should(gomega.Equal(probe.expected), probe.tags[0])
+	}
+}
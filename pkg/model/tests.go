@@ -3,6 +3,8 @@ package model
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -12,7 +14,6 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/denis-tingajkin/cloudtest/pkg/config"
-	"github.com/denis-tingajkin/cloudtest/pkg/execmanager"
 	"github.com/denis-tingajkin/cloudtest/pkg/utils"
 )
 
@@ -34,6 +35,8 @@ const (
 	StatusSkippedSinceNoClusters
 	// StatusRerunRequest - a test was requested its re-run
 	StatusRerunRequest
+	// StatusSkippedByTest - test was executed and skipped itself, like with t.Skip.
+	StatusSkippedByTest
 )
 
 // TestEntryExecution - represent one test execution.
@@ -55,44 +58,85 @@ const (
 
 // TestEntry - represent one found test
 type TestEntry struct {
-	Name            string // Test name
-	Tags            string // A list of tags
-	Key             string // Unique key
+	Name            string        // Test name
+	Package         string        // Import path of go test package
+	PackageRoot     string        // A folder go commands are executed in
+	PackageDir      string        // A folder of go test package, test binary is executed in
+	Binary          string        // A compiled go test binary
+	Tags            string        // A list of tags
+	Timeout         time.Duration // Test timeout, overrides timeout of execution if set
+	ClusterCount    int           // A number of clusters required for test, overrides cluster-count of execution if set
+	Providers       []string      // Cluster providers test is executed on, overrides cluster-selector of execution if set
+	RetestCount     int           // A number of test restarts on retest request, overrides retest count if set
+	Key             string        // Unique key
 	ExecutionConfig *config.Execution
 
 	Executions []TestEntryExecution
 	Duration   time.Duration
 	Started    time.Time
 
-	RunScript string
+	RunScript config.Script // A script of shell test
 
-	Kind   TestEntryKind
-	Status Status
+	Kind    TestEntryKind
+	Status  Status
+	Results []*TestResult // Results of test and its subtests reported by last execution
 	sync.Mutex
 	SkipMessage         string
 	ArtifactDirectories []string
 }
 
-// GetTestConfiguration - Return list of available tests by calling of gotest --list .* $root -tag "" and parsing of output.
-func GetTestConfiguration(manager execmanager.ExecutionManager, root string, source config.ExecutionSource) (map[string]*TestEntry, error) {
-	allTests, err1 := getTests(manager, root)
+// QualifiedName - return test name with its package import path, like github.com/org/repo/test.TestName.
+func (t *TestEntry) QualifiedName() string {
+	if t.Package == "" {
+		return t.Name
+	}
+	return t.Package + "." + t.Name
+}
+
+// Matches - check if name is a name or a qualified name of test or of its parent test.
+func (t *TestEntry) Matches(name string) bool {
+	for _, n := range []string{t.Name, t.QualifiedName()} {
+		if name == n || strings.HasPrefix(n, name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// RunPattern - return go test -run pattern matching only this test, every level of subtest name is matched exactly.
+func (t *TestEntry) RunPattern() string {
+	var levels []string
+	for _, level := range strings.Split(t.Name, "/") {
+		levels = append(levels, "^("+regexp.QuoteMeta(level)+`)\z`)
+	}
+	return strings.Join(levels, "/")
+}
+
+// GetTestConfiguration - Return list of available tests of packages found in every root, tests of every package are
+// listed with its test binary. If tags of source are set, tests selected by tag expressions are returned, every test
+// is compiled with its resolved tag set. Tests are keyed by qualified name.
+// Packages failed to compile are returned as build failures.
+func GetTestConfiguration(binaries *TestBinaries, roots []string, source config.ExecutionSource) (map[string]*TestEntry, []*BuildFailure, error) {
 	if len(source.Tags) > 0 {
-		tests, err := getTests(manager, root, source.Tags...)
+		expr, err := ParseTagExpressions(source.Tags)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		for key := range allTests {
-			delete(tests, key)
-		}
-		return tests, nil
-	} else if len(source.Tests) > 0 {
+		return getTaggedTests(binaries, roots, expr)
+	}
+	allTests, allFailures, err1 := getAllTests(binaries, roots)
+	if len(source.Tests) > 0 {
 		result := map[string]*TestEntry{}
 		var err error
 		for _, n := range source.Tests {
-			t := allTests[n]
-			if t != nil {
-				result[n] = t
-			} else {
+			found := false
+			for key, t := range allTests {
+				if t.Matches(n) {
+					result[key] = t
+					found = true
+				}
+			}
+			if !found {
 				msg := fmt.Sprintf("test %v not found", n)
 				if err == nil {
 					err = errors.New(msg)
@@ -101,41 +145,156 @@ func GetTestConfiguration(manager execmanager.ExecutionManager, root string, sou
 				}
 			}
 		}
-		return result, err
+		return result, allFailures, err
+	}
+	return allTests, allFailures, err1
+}
+
+// getTaggedTests - probe tests with every subset of expression tags. A tag set of test is a minimal tag set test is
+// listed with, test could have few of them, like for "a || b" build constraint. Test is selected if expression is true
+// for one of its tag sets, it is compiled with this tag set. Build failures are reported for tag sets expression is
+// true for.
+func getTaggedTests(binaries *TestBinaries, roots []string, expr *TagExpression) (map[string]*TestEntry, []*BuildFailure, error) {
+	tags := expr.Tags()
+	if len(tags) > maxExpressionTags {
+		return nil, nil, errors.Errorf("tag expressions use %d tags, no more than %d are supported", len(tags), maxExpressionTags)
+	}
+	result := map[string]*TestEntry{}
+	var failures []*BuildFailure
+	var probed [][]string
+	probes := map[string]map[string]*TestEntry{}
+	for _, subset := range tagSubsets(tags) {
+		tests, subsetFailures, err := getAllTests(binaries, roots, subset...)
+		if err != nil {
+			return nil, nil, err
+		}
+		selected := expr.Eval(subset)
+		if selected {
+			failures = append(failures, subsetFailures...)
+		}
+		for key, t := range tests {
+			if _, ok := result[key]; ok || !selected {
+				continue
+			}
+			minimal := true
+			for _, p := range probed {
+				if _, ok := probes[strings.Join(p, ",")][key]; ok && isProperSubset(p, subset) {
+					minimal = false
+					break
+				}
+			}
+			if minimal {
+				result[key] = t
+			}
+		}
+		probed = append(probed, subset)
+		probes[strings.Join(subset, ",")] = tests
+	}
+	return result, failures, nil
+}
+
+// SplitPackageRoot - split package root into a folder go test should be executed in and a package pattern relative
+// to it, like ./test/integration/... into ./test/integration and ./...
+func SplitPackageRoot(root string) (string, string) {
+	if root == "" {
+		return ".", "."
+	}
+	parts := strings.Split(filepath.ToSlash(root), "/")
+	for idx, part := range parts {
+		if strings.Contains(part, "...") {
+			dir := strings.Join(parts[:idx], "/")
+			if dir == "" && idx == 0 {
+				dir = "."
+			} else if dir == "" {
+				dir = "/"
+			}
+			return filepath.FromSlash(dir), "./" + strings.Join(parts[idx:], "/")
+		}
+	}
+	return root, "."
+}
+
+func getAllTests(binaries *TestBinaries, roots []string, tags ...string) (map[string]*TestEntry, []*BuildFailure, error) {
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+	result := map[string]*TestEntry{}
+	var failures []*BuildFailure
+	for _, root := range roots {
+		tests, rootFailures, err := getTests(binaries, root, tags...)
+		if err != nil {
+			return nil, nil, err
+		}
+		for key, t := range tests {
+			result[key] = t
+		}
+		failures = append(failures, rootFailures...)
 	}
-	return allTests, err1
+	return result, failures, nil
 }
 
-func getTests(manager execmanager.ExecutionManager, dir string, tags ...string) (map[string]*TestEntry, error) {
-	gotestCmd := []string{"go", "test", ".", "--list", ".*"}
+// getTests - list packages matching root, compile their tests and list tests of every package with its test binary.
+// Options of tests are read from annotations of test functions.
+func getTests(binaries *TestBinaries, root string, tags ...string) (map[string]*TestEntry, []*BuildFailure, error) {
+	dir, pattern := SplitPackageRoot(root)
 	tagsStr := strings.Join(tags, ",")
+	listCmd := []string{"go", "list", "-f", "{{.ImportPath}}\t{{.Dir}}{{range .TestGoFiles}}\t{{.}}{{end}}{{range .XTestGoFiles}}\t{{.}}{{end}}"}
 	if len(tagsStr) != 0 {
-		gotestCmd = append(gotestCmd, "-tags", tagsStr)
+		listCmd = append(listCmd, "-tags", tagsStr)
 	}
+	listCmd = append(listCmd, pattern)
 
-	result, err := utils.ExecRead(context.Background(), dir, gotestCmd)
+	packages, err := utils.ExecRead(context.Background(), dir, listCmd)
+	binaries.manager.AddLog("gotest", "find-packages", strings.Join(listCmd, " ")+"\n"+strings.Join(packages, "\n"))
 	if err != nil {
-		logrus.Errorf("Error getting list of tests: %v\nOutput: %v\nCmdLine: %v", err, result, gotestCmd)
-		return nil, err
+		logrus.Errorf("Error getting list of packages: %v\nOutput: %v\nCmdLine: %v", err, packages, listCmd)
+		return nil, nil, err
 	}
 
 	testResult := map[string]*TestEntry{}
-
-	manager.AddLog("gotest", "find-tests", strings.Join(gotestCmd, " ")+"\n"+strings.Join(result, "\n"))
-	for _, testLine := range result {
-		if strings.ContainsAny(testLine, "\t") {
-			special := strings.Split(testLine, "\t")
-			if len(special) == 3 {
-				// This is special case.
-				continue
-			}
-		} else {
-			testName := strings.TrimSpace(testLine)
-			testResult[testName] = &TestEntry{
-				Name: testName,
-				Tags: tagsStr,
+	var failures []*BuildFailure
+	for _, line := range packages {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			// No test files
+			continue
+		}
+		pkg, pkgDir, testFiles := fields[0], fields[1], fields[2:]
+		binary, err := binaries.Build(dir, pkg, tagsStr)
+		if failure, ok := err.(*BuildFailure); ok {
+			failures = append(failures, failure)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		listTestsCmd := []string{binary, "-test.list", ".*"}
+		result, err := utils.ExecRead(context.Background(), pkgDir, listTestsCmd)
+		binaries.manager.AddLog("gotest", "find-tests", strings.Join(listTestsCmd, " ")+"\n"+strings.Join(result, "\n"))
+		if err != nil {
+			logrus.Errorf("Error getting list of tests: %v\nOutput: %v\nCmdLine: %v", err, result, listTestsCmd)
+			return nil, nil, err
+		}
+		pkgTests := map[string]*TestEntry{}
+		for _, testLine := range result {
+			if testName := strings.TrimSpace(testLine); testName != "" {
+				pkgTests[testName] = &TestEntry{
+					Name:        testName,
+					Package:     pkg,
+					PackageRoot: dir,
+					PackageDir:  pkgDir,
+					Binary:      binary,
+					Tags:        tagsStr,
+				}
 			}
 		}
+		if err := applyTestAnnotations(pkgDir, testFiles, pkgTests); err != nil {
+			logrus.Errorf("Error reading test annotations of %s: %v", pkg, err)
+			return nil, nil, err
+		}
+		for _, t := range pkgTests {
+			testResult[t.QualifiedName()] = t
+		}
 	}
-	return testResult, nil
+	return testResult, failures, nil
 }
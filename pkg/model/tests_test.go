@@ -0,0 +1,130 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/execmanager"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+func TestSplitPackageRoot(t *testing.T) {
+	g := gomega.NewWithT(t)
+	for root, expected := range map[string][]string{
+		"":                       {".", "."},
+		".":                      {".", "."},
+		"./sample":               {"./sample", "."},
+		"./...":                  {".", "./..."},
+		"./test/integration/...": {"./test/integration", "./..."},
+		"test/integration/...":   {"test/integration", "./..."},
+		"/src/module/...":        {"/src/module", "./..."},
+	} {
+		dir, pattern := SplitPackageRoot(root)
+		g.Expect([]string{dir, pattern}).Should(gomega.Equal(expected), root)
+	}
+}
+
+func TestFindSubtests(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	fileName := filepath.Join(tmpDir, "sample_test.go")
+	g.Expect(ioutil.WriteFile(fileName, []byte(`package sample
+
+import "testing"
+
+var cases = map[string]int{"one": 1, "two": 2}
+
+func TestMap(t *testing.T) {
+	for name := range cases {
+		t.Run(name, func(t *testing.T) {})
+	}
+}
+
+func TestPointers(t *testing.T) {
+	for _, tc := range []*struct{ title string }{{title: "a b"}, {title: "a b"}} {
+		t.Run(tc.title, func(t *testing.T) {})
+	}
+}
+
+func TestDynamic(t *testing.T) {
+	t.Run("static", func(t *testing.T) {})
+	for i := 0; i < 2; i++ {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {})
+	}
+}
+`), os.ModePerm)).Should(gomega.BeNil())
+
+	names, err := findSubtests([]string{fileName}, "TestMap")
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(names).Should(gomega.ConsistOf("one", "two"))
+
+	names, err = findSubtests([]string{fileName}, "TestPointers")
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(names).Should(gomega.Equal([]string{"a_b", "a_b#01"}))
+
+	names, err = findSubtests([]string{fileName}, "TestDynamic")
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(names).Should(gomega.BeNil())
+}
+
+func TestEntryRunPattern(t *testing.T) {
+	g := gomega.NewWithT(t)
+	test := &TestEntry{Name: "TestTable/case_(1)", Package: "github.com/org/repo/test"}
+	g.Expect(test.RunPattern()).Should(gomega.Equal(`^(TestTable)\z/^(case_\(1\))\z`))
+	g.Expect(test.Matches("TestTable")).Should(gomega.BeTrue())
+	g.Expect(test.Matches("github.com/org/repo/test.TestTable/case_(1)")).Should(gomega.BeTrue())
+	g.Expect(test.Matches("TestTab")).Should(gomega.BeFalse())
+}
+
+func TestSelectedTestConfiguration(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer utils.ClearFolder(tmpDir, false)
+
+	binaries := NewTestBinaries(execmanager.NewExecutionManager(tmpDir))
+	roots := []string{"../tests/sample/annotations"}
+	tests, failures, err := GetTestConfiguration(binaries, roots, config.ExecutionSource{})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(failures).Should(gomega.BeEmpty())
+	g.Expect(len(tests)).Should(gomega.Equal(3))
+
+	names := func(tests map[string]*TestEntry) []string {
+		var result []string
+		for _, test := range tests {
+			result = append(result, test.Name)
+		}
+		return result
+	}
+	tests, _, err = GetTestConfiguration(binaries, roots, config.ExecutionSource{Tests: []string{"TestTimeout", "TestRetry"}})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(names(tests)).Should(gomega.ConsistOf("TestTimeout", "TestRetry"))
+
+	tests, _, err = GetTestConfiguration(binaries, roots, config.ExecutionSource{Tests: []string{"TestRetry", "TestMissing"}})
+	g.Expect(err).Should(gomega.MatchError("test TestMissing not found"))
+	g.Expect(names(tests)).Should(gomega.ConsistOf("TestRetry"))
+}
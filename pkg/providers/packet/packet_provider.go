@@ -45,11 +45,11 @@ type packetProvider struct {
 }
 
 type packetInstance struct {
-	installScript  []string
-	setupScript    []string
-	startScript    []string
-	prepareScript  []string
-	stopScript     []string
+	installScript  config.Script
+	setupScript    config.Script
+	startScript    config.Script
+	prepareScript  config.Script
+	stopScript     config.Script
 	manager        execmanager.ExecutionManager
 	root           string
 	id             string
@@ -62,6 +62,7 @@ type packetInstance struct {
 	packetAuthKey  string
 	keyID          string
 	config         *config.ClusterProviderConfig
+	overrides      string
 	provider       *packetProvider
 	client         *packngo.Client
 	project        *packngo.Project
@@ -77,6 +78,10 @@ func (pi *packetInstance) GetID() string {
 	return pi.id
 }
 
+func (pi *packetInstance) GetOutputs() []string {
+	return pi.shellInterface.GetOutputs()
+}
+
 func (pi *packetInstance) CheckIsAlive() error {
 	if pi.started {
 		return pi.validator.Validate()
@@ -109,9 +114,9 @@ func (pi *packetInstance) Start(timeout time.Duration) (string, error) {
 
 	// Process and prepare environment variables
 	if err = pi.shellInterface.ProcessEnvironment(
-		pi.id, pi.config.Name, pi.root, pi.config.Env, nil); err != nil {
+		pi.id, pi.config.Name, pi.root, pi.config.EnvFile, pi.config.Env, nil); err != nil {
 		logrus.Errorf("error during processing environment variables %v", err)
-		return "", err
+		return "", errors.Wrapf(err, "provider %s", pi.config.Name)
 	}
 
 	// Do prepare
@@ -172,6 +177,9 @@ func (pi *packetInstance) Start(timeout time.Duration) (string, error) {
 	pi.addDeviceContextArguments()
 
 	printableEnv := pi.shellInterface.PrintEnv(pi.shellInterface.GetProcessedEnv())
+	if pi.overrides != "" {
+		printableEnv = fmt.Sprintf("Instance overrides: %s\n%s", pi.overrides, printableEnv)
+	}
 	pi.manager.AddLog(pi.id, "environment", printableEnv)
 
 	// Run start script
@@ -291,7 +299,7 @@ func (pi *packetInstance) createDevice(devCfg *config.DeviceConfig) (*packngo.De
 	var hostName string
 	var err error
 	if hostName, err = utils.SubstituteVariable(devCfg.HostName, environment, pi.shellInterface.GetArguments()); err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "provider %s: device %s: hostname", pi.config.Name, devCfg.Name)
 	}
 
 	devReq := &packngo.DeviceCreateRequest{
@@ -460,7 +468,7 @@ func (pi *packetInstance) doDestroy(writer io.StringWriter, timeout time.Duratio
 func (pi *packetInstance) doInstall(context context.Context) (string, error) {
 	pi.provider.Lock()
 	defer pi.provider.Unlock()
-	if pi.installScript != nil && !pi.provider.installDone[pi.config.Name] {
+	if len(pi.installScript) > 0 && !pi.provider.installDone[pi.config.Name] {
 		pi.provider.installDone[pi.config.Name] = true
 		return pi.shellInterface.RunCmd(context, "install", pi.installScript, nil)
 	}
@@ -594,6 +602,7 @@ func (p *packetProvider) CreateCluster(config *config.ClusterProviderConfig, fac
 	p.Lock()
 	defer p.Unlock()
 	id := fmt.Sprintf("%s-%s", config.Name, p.getProviderID(config.Name))
+	config, overrides := config.ForInstance(p.indexes[config.Name])
 
 	root := path.Join(p.root, id)
 
@@ -602,13 +611,14 @@ func (p *packetProvider) CreateCluster(config *config.ClusterProviderConfig, fac
 		provider:       p,
 		root:           root,
 		id:             id,
+		overrides:      overrides,
 		config:         config,
-		configScript:   config.Scripts[configScript],
-		installScript:  utils.ParseScript(config.Scripts[installScript]),
-		setupScript:    utils.ParseScript(config.Scripts[setupScript]),
-		startScript:    utils.ParseScript(config.Scripts[startScript]),
-		prepareScript:  utils.ParseScript(config.Scripts[prepareScript]),
-		stopScript:     utils.ParseScript(config.Scripts[stopScript]),
+		configScript:   config.Scripts[configScript].String(),
+		installScript:  config.Scripts[installScript],
+		setupScript:    config.Scripts[setupScript],
+		startScript:    config.Scripts[startScript],
+		prepareScript:  config.Scripts[prepareScript],
+		stopScript:     config.Scripts[stopScript],
 		factory:        factory,
 		shellInterface: shell.NewManager(manager, id, config, instanceOptions),
 		params:         instanceOptions,
@@ -635,7 +645,7 @@ func (p *packetProvider) CleanupClusters(ctx context.Context, config *config.Clu
 	// Do prepare
 	if skipInstall := instanceOptions.NoInstall || p.installDone[config.Name]; !skipInstall {
 		if iScript, ok := config.Scripts[installScript]; ok {
-			_, err := shellInterface.RunCmd(ctx, "install", utils.ParseScript(iScript), config.Env)
+			_, err := shellInterface.RunCmd(ctx, "install", iScript, config.Env)
 			if err != nil {
 				logrus.Warnf("Install command for cluster %s finished with error: %v", config.Name, err)
 			} else {
@@ -645,7 +655,7 @@ func (p *packetProvider) CleanupClusters(ctx context.Context, config *config.Clu
 	}
 	p.Unlock()
 
-	_, err := shellInterface.RunCmd(ctx, "cleanup", utils.ParseScript(config.Scripts[cleanupScript]), config.Env)
+	_, err := shellInterface.RunCmd(ctx, "cleanup", config.Scripts[cleanupScript], config.Env)
 	if err != nil {
 		logrus.Warnf("Cleanup command for cluster %s finished with error: %v", config.Name, err)
 	}
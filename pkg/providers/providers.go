@@ -37,6 +37,8 @@ type ClusterInstance interface {
 	IsRunning() bool
 	CheckIsAlive() error
 	GetID() string
+	// GetOutputs - return outputs of cluster scripts as KEY=VALUE environment variables of tests.
+	GetOutputs() []string
 }
 
 // ClusterProvider - provides operations with clusters
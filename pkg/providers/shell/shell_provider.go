@@ -41,20 +41,21 @@ type shellProvider struct {
 }
 
 type shellInstance struct {
-	installScript      []string
-	startScript        []string
-	prepareScript      []string
-	stopScript         []string
+	installScript      config.Script
+	startScript        config.Script
+	prepareScript      config.Script
+	stopScript         config.Script
 	manager            execmanager.ExecutionManager
 	root               string
 	id                 string
 	configScript       string
-	zoneSelectorScript []string
+	zoneSelectorScript config.Script
 	factory            k8s.ValidationFactory
 	validator          k8s.KubernetesValidator
 	configLocation     string
 	shellInterface     shell.Manager
 	config             *config.ClusterProviderConfig
+	overrides          string
 	provider           *shellProvider
 	params             providers.InstanceOptions
 	started            bool
@@ -64,6 +65,10 @@ func (si *shellInstance) GetID() string {
 	return si.id
 }
 
+func (si *shellInstance) GetOutputs() []string {
+	return si.shellInterface.GetOutputs()
+}
+
 func (si *shellInstance) CheckIsAlive() error {
 	if si.started {
 		return si.validator.Validate()
@@ -109,15 +114,18 @@ func (si *shellInstance) Start(timeout time.Duration) (string, error) {
 
 	// Process and prepare environment variables
 	err = si.shellInterface.ProcessEnvironment(
-		si.id, si.config.Name, si.root, si.config.Env,
+		si.id, si.config.Name, si.root, si.config.EnvFile, si.config.Env,
 		map[string]string{
 			"zone-selector": selectedZone,
 		})
 	if err != nil {
-		return "", err
+		return "", errors.Wrapf(err, "provider %s", si.config.Name)
 	}
 
 	printableEnv := si.shellInterface.PrintEnv(si.shellInterface.GetProcessedEnv())
+	if si.overrides != "" {
+		printableEnv = fmt.Sprintf("Instance overrides: %s\n%s", si.overrides, printableEnv)
+	}
 	si.manager.AddLog(si.id, "environment", printableEnv)
 
 	// Run start script
@@ -199,14 +207,14 @@ func (si *shellInstance) doDestroy(writer io.StringWriter, timeout time.Duration
 func (si *shellInstance) doInstall(context context.Context) (string, error) {
 	si.provider.Lock()
 	defer si.provider.Unlock()
-	if si.installScript != nil && !si.provider.installDone[si.config.Name] {
+	if len(si.installScript) > 0 && !si.provider.installDone[si.config.Name] {
 		si.provider.installDone[si.config.Name] = true
 		return si.shellInterface.RunCmd(context, "install", si.installScript, nil)
 	}
 	return "", nil
 }
 
-func selectZone(ctx context.Context, shellInterface shell.Manager, zoneSelectorScript []string) (string, error) {
+func selectZone(ctx context.Context, shellInterface shell.Manager, zoneSelectorScript config.Script) (string, error) {
 	if len(zoneSelectorScript) > 0 {
 		return "", nil
 	}
@@ -249,6 +257,7 @@ func (p *shellProvider) CreateCluster(config *config.ClusterProviderConfig, fact
 	p.Lock()
 	defer p.Unlock()
 	id := fmt.Sprintf("%s-%s", config.Name, p.getProviderID(config.Name))
+	config, overrides := config.ForInstance(p.indexes[config.Name])
 
 	root := path.Join(p.root, id)
 
@@ -257,13 +266,14 @@ func (p *shellProvider) CreateCluster(config *config.ClusterProviderConfig, fact
 		provider:           p,
 		root:               root,
 		id:                 id,
+		overrides:          overrides,
 		config:             config,
-		configScript:       config.Scripts[configScript],
-		installScript:      utils.ParseScript(config.Scripts[installScript]),
-		startScript:        utils.ParseScript(config.Scripts[startScript]),
-		prepareScript:      utils.ParseScript(config.Scripts[prepareScript]),
-		stopScript:         utils.ParseScript(config.Scripts[stopScript]),
-		zoneSelectorScript: utils.ParseScript(config.Scripts[zoneSelector]),
+		configScript:       config.Scripts[configScript].String(),
+		installScript:      config.Scripts[installScript],
+		startScript:        config.Scripts[startScript],
+		prepareScript:      config.Scripts[prepareScript],
+		stopScript:         config.Scripts[stopScript],
+		zoneSelectorScript: config.Scripts[zoneSelector],
 		factory:            factory,
 		shellInterface:     shell.NewManager(manager, id, config, instanceOptions),
 		params:             instanceOptions,
@@ -289,7 +299,7 @@ func (p *shellProvider) CleanupClusters(ctx context.Context, config *config.Clus
 	// Do prepare
 	if skipInstall := instanceOptions.NoInstall || p.installDone[config.Name]; !skipInstall {
 		if iScript, ok := config.Scripts[installScript]; ok {
-			_, err := shellInterface.RunCmd(ctx, "install", utils.ParseScript(iScript), config.Env)
+			_, err := shellInterface.RunCmd(ctx, "install", iScript, config.Env)
 			if err != nil {
 				logrus.Warnf("Install command for cluster %s finished with error: %v", config.Name, err)
 			} else {
@@ -302,7 +312,7 @@ func (p *shellProvider) CleanupClusters(ctx context.Context, config *config.Clus
 	var selectedZone string
 	var err error
 	if zScript, ok := config.Scripts[zoneSelector]; ok {
-		selectedZone, err = selectZone(ctx, shellInterface, utils.ParseScript(zScript))
+		selectedZone, err = selectZone(ctx, shellInterface, zScript)
 		if err != nil {
 			logrus.Warnf("Select zone command for cluster %s finished with error: %v", config.Name, err)
 			return
@@ -311,7 +321,7 @@ func (p *shellProvider) CleanupClusters(ctx context.Context, config *config.Clus
 
 	// Process and prepare environment variables
 	err = shellInterface.ProcessEnvironment(
-		clusterID, config.Name, p.root, config.Env,
+		clusterID, config.Name, p.root, config.EnvFile, config.Env,
 		map[string]string{
 			"zone-selector": selectedZone,
 		})
@@ -323,7 +333,7 @@ func (p *shellProvider) CleanupClusters(ctx context.Context, config *config.Clus
 	printableEnv := shellInterface.PrintEnv(shellInterface.GetProcessedEnv())
 	manager.AddLog(clusterID, "environment", printableEnv)
 
-	_, err = shellInterface.RunCmd(ctx, "cleanup", utils.ParseScript(config.Scripts[cleanupScript]), nil)
+	_, err = shellInterface.RunCmd(ctx, "cleanup", config.Scripts[cleanupScript], nil)
 	if err != nil {
 		logrus.Warnf("Cleanup command for cluster %s finished with error: %v", config.Name, err)
 	}
@@ -39,8 +39,10 @@ type TestCase struct {
 	Name        string       `xml:"name,attr"`
 	Time        string       `xml:"time,attr"`
 	Cluster     string       `xml:"cluster_instance,attr"`
+	Properties  []*Property  `xml:"properties>property,omitempty"`
 	SkipMessage *SkipMessage `xml:"skipped,omitempty"`
 	Failure     *Failure     `xml:"failure,omitempty"`
+	TestCases   []*TestCase  // Nested testcases of subtests
 }
 
 // SkipMessage - JUnitSkipMessage contains the reason why a testcase was skipped.
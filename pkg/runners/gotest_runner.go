@@ -5,8 +5,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/denis-tingajkin/cloudtest/pkg/model"
 	"github.com/denis-tingajkin/cloudtest/pkg/shell"
 	"github.com/denis-tingajkin/cloudtest/pkg/utils"
@@ -15,15 +18,24 @@ import (
 type goTestRunner struct {
 	test        *model.TestEntry
 	cmdLine     string
+	dir         string
 	envMgr      shell.EnvironmentManager
 	artifactDir string
+	envErr      error
 }
 
 func (runner *goTestRunner) Run(timeoutCtx context.Context, env []string, writer *bufio.Writer) error {
+	if runner.envErr != nil {
+		return runner.envErr
+	}
 	logger := func(s string) {}
 	cmdEnv := append(runner.envMgr.GetProcessedEnv(), env...)
-	_, err := utils.RunCommand(timeoutCtx, runner.cmdLine, runner.test.ExecutionConfig.PackageRoot,
-		logger, writer, cmdEnv, map[string]string{"artifact-dir": runner.artifactDir}, false)
+	events := newTest2jsonWriter(writer)
+	eventsWriter := bufio.NewWriter(events)
+	_, err := utils.RunCommand(timeoutCtx, runner.cmdLine, runner.dir,
+		logger, eventsWriter, cmdEnv, map[string]string{"artifact-dir": runner.artifactDir}, false)
+	_ = eventsWriter.Flush()
+	runner.test.Results = events.Results()
 	return err
 }
 
@@ -31,13 +43,30 @@ func (runner *goTestRunner) GetCmdLine() string {
 	return runner.cmdLine
 }
 
-// NewGoTestRunner - creates go test runner
+// NewGoTestRunner - creates go test runner, test is executed in its own package with a test binary compiled during
+// discovery, or with go test if there is no binary. Test output is converted to json events by test2json to collect
+// results of test and its subtests.
 func NewGoTestRunner(ids string, test *model.TestEntry, timeout time.Duration) TestRunner {
-	cmdLine := fmt.Sprintf("go test . -test.timeout %v -count 1 --run \"^(%s)$\\\\z\" --tags \"%s\" --test.v",
-		timeout, test.Name, test.Tags)
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	runPattern := escape.Replace(test.RunPattern())
+	var cmdLine, dir string
+	if test.Binary != "" {
+		cmdLine = fmt.Sprintf("go tool test2json -p %s \"%s\" -test.v -test.timeout %v -test.count 1 -test.run \"%s\"",
+			test.Package, escape.Replace(test.Binary), timeout, runPattern)
+		dir = test.PackageDir
+	} else {
+		pkg := test.Package
+		if pkg == "" {
+			pkg = "."
+		}
+		cmdLine = fmt.Sprintf("go test %s -json -test.timeout %v -count 1 --run \"%s\" --tags \"%s\"",
+			pkg, timeout, runPattern, test.Tags)
+		dir = test.PackageRoot
+	}
 
 	envMgr := shell.NewEnvironmentManager()
-	_ = envMgr.ProcessEnvironment(ids, "gotest", os.TempDir(), test.ExecutionConfig.Env, map[string]string{})
+	// An error of environment is reported as test failure.
+	envErr := envMgr.ProcessEnvironment(ids, "gotest", os.TempDir(), test.ExecutionConfig.EnvFile, test.ExecutionConfig.Env, map[string]string{"test-name": test.Name})
 	artifactDir := ""
 	if len(test.ArtifactDirectories) > 0 {
 		artifactDir = test.ArtifactDirectories[len(test.ArtifactDirectories)-1]
@@ -45,7 +74,9 @@ func NewGoTestRunner(ids string, test *model.TestEntry, timeout time.Duration) T
 	return &goTestRunner{
 		test:        test,
 		cmdLine:     cmdLine,
+		dir:         dir,
 		envMgr:      envMgr,
 		artifactDir: artifactDir,
+		envErr:      errors.Wrapf(envErr, "execution %s", test.ExecutionConfig.Name),
 	}
 }
@@ -0,0 +1,51 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runners
+
+import (
+	"bytes"
+)
+
+// lineBuffer - collects written output and splits it into complete lines.
+type lineBuffer struct {
+	data []byte
+}
+
+// lines - append p to buffer and return all complete lines, with line endings.
+func (b *lineBuffer) lines(p []byte) [][]byte {
+	b.data = append(b.data, p...)
+	var result [][]byte
+	for {
+		idx := bytes.IndexByte(b.data, '\n')
+		if idx < 0 {
+			break
+		}
+		result = append(result, b.data[:idx+1])
+		b.data = b.data[idx+1:]
+	}
+	return result
+}
+
+// rest - return incomplete last line with a line ending added, nil if there is no incomplete line.
+func (b *lineBuffer) rest() []byte {
+	if len(b.data) == 0 {
+		return nil
+	}
+	result := append(b.data, '\n')
+	b.data = nil
+	return result
+}
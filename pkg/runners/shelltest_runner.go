@@ -4,9 +4,13 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
+	"github.com/pkg/errors"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/execmanager"
 	"github.com/denis-tingajkin/cloudtest/pkg/model"
 	"github.com/denis-tingajkin/cloudtest/pkg/shell"
 	"github.com/denis-tingajkin/cloudtest/pkg/utils"
@@ -16,27 +20,71 @@ type shellTestRunner struct {
 	test        *model.TestEntry
 	envMgr      shell.EnvironmentManager
 	artifactDir string
+	envErr      error
 	id          string
+	manager     execmanager.ExecutionManager
 }
 
 func (runner *shellTestRunner) Run(timeoutCtx context.Context, env []string, writer *bufio.Writer) error {
-	err := runner.runCmd(timeoutCtx, utils.ParseScript(runner.test.RunScript), env, writer)
+	if runner.envErr != nil {
+		return runner.envErr
+	}
+	if runner.test.ExecutionConfig.Format != "tap" {
+		return runner.runSteps(timeoutCtx, env, writer)
+	}
+	// Test points of TAP output are reported as subtests.
+	tap := newTapWriter(writer, runner.test.Name)
+	tapWriter := bufio.NewWriter(tap)
+	err := runner.runSteps(timeoutCtx, env, tapWriter)
+	_ = tapWriter.Flush()
+	results, tapErr := tap.Results()
+	runner.test.Results = results
+	if err == nil {
+		err = tapErr
+	}
 	return err
 }
 
-func (runner *shellTestRunner) runCmd(context context.Context, script, env []string, writer *bufio.Writer) error {
-	for _, cmd := range script {
+// runSteps - run steps of test script, output of every step is written to test output and to a log file of step.
+func (runner *shellTestRunner) runSteps(ctx context.Context, env []string, writer *bufio.Writer) error {
+	return shell.RunSteps(ctx, runner.test.RunScript, &shell.StepOptions{
+		Name: runner.test.Name,
+		Env:  append(runner.envMgr.GetProcessedEnv(), env...),
+		Args: map[string]string{"artifacts-dir": runner.artifactDir},
+		Out:  writer,
+		OpenLog: func(step string) (io.WriteCloser, error) {
+			if step == "" || runner.manager == nil {
+				return nil, nil
+			}
+			_, file, err := runner.manager.OpenFileTest(runner.id, runner.test.Name, "run-"+step)
+			return file, err
+		},
+	}, runner.runCmd)
+}
+
+func (runner *shellTestRunner) runCmd(context context.Context, script string, env []string, writer *bufio.Writer) error {
+	args := map[string]string{"artifacts-dir": runner.artifactDir}
+	if runner.test.ExecutionConfig.Shell == utils.ShellBash {
+		logger := func(s string) {
+		}
+		_, err := utils.RunScript(context, script, "", logger, writer, env, args, false)
+		if err != nil {
+			_, _ = writer.WriteString(fmt.Sprintf("error running script: %v\n", err))
+			_ = writer.Flush()
+		}
+		return err
+	}
+	for _, cmd := range utils.ParseScript(script) {
 		if strings.TrimSpace(cmd) == "" {
 			continue
 		}
 
-		cmdEnv := append(runner.envMgr.GetProcessedEnv(), env...)
 		_, _ = writer.WriteString(fmt.Sprintf(">>>>>>Running: %s:<<<<<<\n", cmd))
 		_ = writer.Flush()
 
 		logger := func(s string) {
 		}
-		_, err := utils.RunCommand(context, cmd, "", logger, writer, cmdEnv, map[string]string{"artifacts-dir": runner.artifactDir}, false)
+		_, err := utils.RunCommand(context, cmd, "", logger, writer, env, args, false)
 		if err != nil {
 			_, _ = writer.WriteString(fmt.Sprintf("error running command: %v\n", err))
 			_ = writer.Flush()
@@ -47,13 +95,14 @@ func (runner *shellTestRunner) runCmd(context context.Context, script, env []str
 }
 
 func (runner *shellTestRunner) GetCmdLine() string {
-	return runner.test.RunScript
+	return runner.test.RunScript.String()
 }
 
 // NewShellTestRunner - creates a new shell script test runner.
-func NewShellTestRunner(ids string, test *model.TestEntry) TestRunner {
+func NewShellTestRunner(ids string, test *model.TestEntry, manager execmanager.ExecutionManager) TestRunner {
 	envMgr := shell.NewEnvironmentManager()
-	_ = envMgr.ProcessEnvironment(ids, "shellrun", os.TempDir(), test.ExecutionConfig.Env, map[string]string{})
+	// An error of environment is reported as test failure.
+	envErr := envMgr.ProcessEnvironment(ids, "shellrun", os.TempDir(), test.ExecutionConfig.EnvFile, test.ExecutionConfig.Env, map[string]string{"test-name": test.Name})
 	artifactDir := ""
 	if len(test.ArtifactDirectories) > 0 {
 		artifactDir = test.ArtifactDirectories[len(test.ArtifactDirectories)-1]
@@ -61,7 +110,9 @@ func NewShellTestRunner(ids string, test *model.TestEntry) TestRunner {
 	return &shellTestRunner{
 		id:          ids,
 		test:        test,
+		manager:     manager,
 		envMgr:      envMgr,
 		artifactDir: artifactDir,
+		envErr:      errors.Wrapf(envErr, "execution %s", test.ExecutionConfig.Name),
 	}
 }
@@ -0,0 +1,196 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runners
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/model"
+)
+
+var (
+	tapPlan  = regexp.MustCompile(`^1\.\.(\d+)`)
+	tapPoint = regexp.MustCompile(`^(not ok|ok)\b\s*(\d*)\s*(?:-\s*)?([^#]*?)\s*(?:#\s*(.*))?$`)
+)
+
+// tapWriter - parses TAP output of shell test, output is written to out as is and every test point is collected as
+// a result of subtest of test. Diagnostics following a test point are attached to its output, diagnostics before first
+// test point are attached to output of test itself. Indented test points are subtests of test point following them.
+type tapWriter struct {
+	sync.Mutex
+	out     *bufio.Writer
+	buffer  lineBuffer
+	test    string
+	points  []*tapTestPoint
+	pending map[int][]*tapTestPoint // Subtest points of every nesting level waiting for their parent point.
+	last    *tapTestPoint
+	leading strings.Builder
+	plan    int
+	bailOut *string
+}
+
+type tapTestPoint struct {
+	name     string
+	status   model.Status
+	output   strings.Builder
+	children []*tapTestPoint
+}
+
+func newTapWriter(out *bufio.Writer, test string) *tapWriter {
+	return &tapWriter{
+		out:     out,
+		test:    test,
+		pending: map[int][]*tapTestPoint{},
+		plan:    -1,
+	}
+}
+
+func (w *tapWriter) Write(p []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+	for _, line := range w.buffer.lines(p) {
+		if _, err := w.out.Write(line); err != nil {
+			return 0, err
+		}
+		w.processLine(strings.TrimRight(string(line), "\r\n"))
+	}
+	return len(p), w.out.Flush()
+}
+
+func (w *tapWriter) processLine(line string) {
+	trimmed := strings.TrimSpace(line)
+	if m := tapPlan.FindStringSubmatch(trimmed); m != nil {
+		// Plans of subtests are not checked.
+		if line == trimmed {
+			w.plan, _ = strconv.Atoi(m[1])
+		}
+		return
+	}
+	if strings.HasPrefix(trimmed, "Bail out!") {
+		reason := strings.TrimSpace(strings.TrimPrefix(trimmed, "Bail out!"))
+		w.bailOut = &reason
+		return
+	}
+	if strings.HasPrefix(trimmed, "TAP version") || strings.HasPrefix(trimmed, "# Subtest") {
+		return
+	}
+	m := tapPoint.FindStringSubmatch(trimmed)
+	if m == nil {
+		// A diagnostic or YAML block of last test point.
+		if w.last != nil {
+			w.last.output.WriteString(line + "\n")
+		} else if trimmed != "" {
+			w.leading.WriteString(line + "\n")
+		}
+		return
+	}
+	level := (len(line) - len(strings.TrimLeft(line, " \t"))) / 4
+	number := len(w.pending[level]) + 1
+	if level == 0 {
+		number = len(w.points) + 1
+	}
+	if m[2] != "" {
+		number, _ = strconv.Atoi(m[2])
+	}
+	point := &tapTestPoint{
+		name:     strconv.Itoa(number),
+		status:   model.StatusSuccess,
+		children: w.pending[level+1],
+	}
+	delete(w.pending, level+1)
+	if m[3] != "" {
+		point.name += " - " + strings.ReplaceAll(m[3], "/", "_")
+	}
+	directive := strings.ToUpper(m[4])
+	switch {
+	case strings.HasPrefix(directive, "SKIP") || strings.HasPrefix(directive, "TODO"):
+		point.status = model.StatusSkippedByTest
+		point.output.WriteString(m[4] + "\n")
+	case m[1] == "not ok":
+		point.status = model.StatusFailed
+	}
+	if level == 0 {
+		w.points = append(w.points, point)
+	} else {
+		w.pending[level] = append(w.pending[level], point)
+	}
+	w.last = point
+}
+
+// Results - return results of test and all test points and an error if any of test points is failed, test is bailed
+// out or not all planned test points are reported.
+func (w *tapWriter) Results() ([]*model.TestResult, error) {
+	w.Lock()
+	defer w.Unlock()
+	if rest := w.buffer.rest(); rest != nil {
+		w.processLine(strings.TrimRight(string(rest), "\r\n"))
+	}
+	var failed []string
+	for _, p := range w.points {
+		if p.status == model.StatusFailed {
+			failed = append(failed, p.name)
+		}
+	}
+	var problems []string
+	if len(failed) > 0 {
+		problems = append(problems, fmt.Sprintf("failed test points: %s", strings.Join(failed, ", ")))
+	}
+	if w.bailOut != nil {
+		problems = append(problems, fmt.Sprintf("bailed out: %s", *w.bailOut))
+	}
+	if w.plan == -1 && len(w.points) == 0 {
+		problems = append(problems, "no test points are reported")
+	}
+	if w.plan >= 0 && w.plan != len(w.points) {
+		problems = append(problems, fmt.Sprintf("%d test points are planned, but %d are reported", w.plan, len(w.points)))
+	}
+	test := &model.TestResult{
+		Name:   w.test,
+		Status: model.StatusSuccess,
+		Output: w.leading.String(),
+	}
+	if len(problems) > 0 {
+		test.Status = model.StatusFailed
+	}
+	results := append([]*model.TestResult{test}, tapResults(w.test, w.points)...)
+	if len(problems) > 0 {
+		return results, errors.Errorf("TAP %s", strings.Join(problems, "; "))
+	}
+	return results, nil
+}
+
+// tapResults - return results of test points and all their subtests, named as subtests of parent.
+func tapResults(parent string, points []*tapTestPoint) []*model.TestResult {
+	var result []*model.TestResult
+	for _, p := range points {
+		name := parent + "/" + p.name
+		result = append(result, &model.TestResult{
+			Name:   name,
+			Status: p.status,
+			Output: p.output.String(),
+		})
+		result = append(result, tapResults(name, p.children)...)
+	}
+	return result
+}
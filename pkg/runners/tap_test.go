@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runners
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/model"
+)
+
+func TestTapWriter(t *testing.T) {
+	g := gomega.NewWithT(t)
+	out := &strings.Builder{}
+	tap := newTapWriter(bufio.NewWriter(out), "test.sh")
+	output := `TAP version 14
+# cluster: shell
+1..2
+# Subtest: deployment
+    1..2
+    ok 1 - pods are created
+    not ok 2 - pods are ready # TODO slow start
+    # 1 of 3 pods is ready
+ok 1 - deployment
+not ok 2 - cleanup
+# namespace is not deleted
+`
+	_, err := tap.Write([]byte(output))
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(out.String()).Should(gomega.Equal(output))
+
+	results, err := tap.Results()
+	g.Expect(err).Should(gomega.MatchError("TAP failed test points: 2 - cleanup"))
+	g.Expect(results).Should(gomega.Equal([]*model.TestResult{
+		{Name: "test.sh", Status: model.StatusFailed, Output: "# cluster: shell\n"},
+		{Name: "test.sh/1 - deployment", Status: model.StatusSuccess},
+		{Name: "test.sh/1 - deployment/1 - pods are created", Status: model.StatusSuccess},
+		{Name: "test.sh/1 - deployment/2 - pods are ready", Status: model.StatusSkippedByTest,
+			Output: "TODO slow start\n    # 1 of 3 pods is ready\n"},
+		{Name: "test.sh/2 - cleanup", Status: model.StatusFailed, Output: "# namespace is not deleted\n"},
+	}))
+}
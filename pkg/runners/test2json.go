@@ -0,0 +1,123 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runners
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/model"
+)
+
+// testEvent - an event of go test json output, see go doc cmd/test2json.
+type testEvent struct {
+	Action  string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// test2jsonWriter - parses go test json events, test output is written to out as plain text and results of tests
+// and subtests are collected. Lines which are not json events, like build errors, are written to out as is.
+type test2jsonWriter struct {
+	sync.Mutex
+	out     *bufio.Writer
+	buffer  lineBuffer
+	results []*model.TestResult
+	outputs map[string]*strings.Builder
+}
+
+func newTest2jsonWriter(out *bufio.Writer) *test2jsonWriter {
+	return &test2jsonWriter{
+		out:     out,
+		outputs: map[string]*strings.Builder{},
+	}
+}
+
+func (w *test2jsonWriter) Write(p []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+	for _, line := range w.buffer.lines(p) {
+		if err := w.processLine(line); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), w.out.Flush()
+}
+
+func (w *test2jsonWriter) processLine(line []byte) error {
+	event := &testEvent{}
+	if !bytes.HasPrefix(line, []byte("{")) || json.Unmarshal(line, event) != nil {
+		_, err := w.out.Write(line)
+		return err
+	}
+	if event.Output != "" {
+		if _, err := io.WriteString(w.out, event.Output); err != nil {
+			return err
+		}
+	}
+	if event.Test == "" {
+		return nil
+	}
+	output, ok := w.outputs[event.Test]
+	if !ok {
+		output = &strings.Builder{}
+		w.outputs[event.Test] = output
+		w.results = append(w.results, &model.TestResult{Name: event.Test})
+	}
+	output.WriteString(event.Output)
+
+	status := model.StatusAdded
+	switch event.Action {
+	case "pass":
+		status = model.StatusSuccess
+	case "fail":
+		status = model.StatusFailed
+	case "skip":
+		status = model.StatusSkippedByTest
+	default:
+		return nil
+	}
+	for _, r := range w.results {
+		if r.Name == event.Test {
+			r.Status = status
+			r.Duration = time.Duration(event.Elapsed * float64(time.Second))
+		}
+	}
+	return nil
+}
+
+// Results - return results of all reported tests and subtests, tests not completed are reported as failed.
+func (w *test2jsonWriter) Results() []*model.TestResult {
+	w.Lock()
+	defer w.Unlock()
+	if rest := w.buffer.rest(); rest != nil {
+		_ = w.processLine(rest)
+	}
+	for _, r := range w.results {
+		r.Output = w.outputs[r.Name].String()
+		if r.Status == model.StatusAdded {
+			r.Status = model.StatusFailed
+		}
+	}
+	return w.results
+}
@@ -5,22 +5,32 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
 	"github.com/denis-tingajkin/cloudtest/pkg/utils"
 )
 
 // EnvironmentManager - manages environment variables.
 type EnvironmentManager interface {
 	// ProcessEnvironment - process substitute of environment variables with arguments.
-	ProcessEnvironment(clusterID, providerName, tempDir string, env []string, extraArgs map[string]string) error
+	// Variables of env files are processed before env and their values are treated as secrets.
+	ProcessEnvironment(clusterID, providerName, tempDir string, envFiles config.EnvFiles, env []string, extraArgs map[string]string) error
 	// GetProcessedEnv - return substituted environment variables
 	GetProcessedEnv() []string
 	// AddExtraArgs - add argument to map of substitute arguments $(arg) = value
 	AddExtraArgs(key, value string)
+	// AddOutput - add a script output as argument $(key) and as environment variable of later commands.
+	AddOutput(key, value string)
+	// GetOutputs - return outputs added, as KEY=VALUE environment variables
+	GetOutputs() []string
+	// GetSecrets - return values of variables loaded from env files, they should be masked in printed output
+	GetSecrets() []string
 	//
 	GetArguments() map[string]string
 }
@@ -29,6 +39,8 @@ type environmentManager struct {
 	processedEnv   []string
 	configLocation string
 	finalArgs      map[string]string
+	outputs        []string
+	secrets        []string
 }
 
 func (em *environmentManager) GetArguments() map[string]string {
@@ -44,6 +56,29 @@ func (em *environmentManager) AddExtraArgs(key, value string) {
 	em.finalArgs[key] = value
 }
 
+func (em *environmentManager) AddOutput(key, value string) {
+	em.AddExtraArgs(key, value)
+	em.processedEnv = setVariable(em.processedEnv, key, value)
+	em.outputs = setVariable(em.outputs, key, value)
+}
+
+func (em *environmentManager) GetOutputs() []string {
+	return em.outputs
+}
+
+// setVariable - replace value of variable in KEY=VALUE list or add it.
+func setVariable(env []string, key, value string) []string {
+	variable := fmt.Sprintf("%s=%s", key, value)
+	for idx, e := range env {
+		if strings.HasPrefix(e, key+"=") {
+			result := append([]string{}, env...)
+			result[idx] = variable
+			return result
+		}
+	}
+	return append(env[:len(env):len(env)], variable)
+}
+
 func (em *environmentManager) GetProcessedEnv() []string {
 	return em.processedEnv
 }
@@ -52,7 +87,15 @@ func (em *environmentManager) GetConfigLocation() string {
 	return em.configLocation
 }
 
-func (em *environmentManager) ProcessEnvironment(clusterID, providerName, tempDir string, env []string, extraArgs map[string]string) error {
+func (em *environmentManager) GetSecrets() []string {
+	return em.secrets
+}
+
+func (em *environmentManager) ProcessEnvironment(clusterID, providerName, tempDir string, envFiles config.EnvFiles, env []string, extraArgs map[string]string) error {
+	// Environment and outputs of previous start of cluster are replaced.
+	em.processedEnv = nil
+	em.outputs = nil
+	em.secrets = nil
 	environment := map[string]string{}
 
 	for _, k := range os.Environ() {
@@ -68,11 +111,7 @@ func (em *environmentManager) ProcessEnvironment(clusterID, providerName, tempDi
 	todayYear := fmt.Sprintf("%d", today.Year())
 	todayMonth := fmt.Sprintf("%d", today.Month())
 	todayDay := fmt.Sprintf("%d", today.Day())
-	for _, rawVarName := range env {
-		varName, varValue, err := utils.ParseVariable(rawVarName)
-		if err != nil {
-			return err
-		}
+	substitutionArgs := func() map[string]string {
 		randNum, err := rand.Int(rand.Reader, big.NewInt(1000000))
 		randValue := ""
 		if err != nil {
@@ -101,20 +140,57 @@ func (em *environmentManager) ProcessEnvironment(clusterID, providerName, tempDi
 		for k, v := range extraArgs {
 			args[k] = v
 		}
+		return args
+	}
 
-		varValue, err = utils.SubstituteVariable(varValue, environment, args)
+	processVariable := func(rawVarName string, secret bool) error {
+		varName, varValue, err := utils.ParseVariable(rawVarName)
 		if err != nil {
 			return err
 		}
+		varValue, err = utils.SubstituteVariable(varValue, environment, substitutionArgs())
+		if err != nil {
+			return errors.Wrapf(err, "env %s", varName)
+		}
 
 		// Now we need to parse  line and replace all ${VAR_NAME} with real and processed environment variables.
 
 		if varName == "KUBECONFIG" {
 			em.configLocation = varValue
 		}
+		if secret && varValue != "" {
+			em.secrets = append(em.secrets, varValue)
+		}
 
 		environment[varName] = varValue
-		em.processedEnv = append(em.processedEnv, fmt.Sprintf("%s=%s", varName, varValue))
+		em.processedEnv = setVariable(em.processedEnv, varName, varValue)
+		return nil
+	}
+
+	// Variables of env files go first, so env could refer to them.
+	for _, envFile := range envFiles {
+		fileName, err := utils.SubstituteVariable(envFile.Path, environment, substitutionArgs())
+		if err != nil {
+			return errors.Wrapf(err, "env-file %s", envFile.Path)
+		}
+		variables, err := utils.ReadEnvFile(fileName)
+		if os.IsNotExist(errors.Cause(err)) && envFile.Optional {
+			logrus.Infof("Optional env file %s is not found, skipping", fileName)
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "env-file %s", envFile.Path)
+		}
+		for _, variable := range variables {
+			if err = processVariable(variable, true); err != nil {
+				return errors.Wrapf(err, "env-file %s", envFile.Path)
+			}
+		}
+	}
+	for _, rawVarName := range env {
+		if err := processVariable(rawVarName, false); err != nil {
+			return err
+		}
 	}
 
 	em.finalArgs = map[string]string{
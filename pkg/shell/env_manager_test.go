@@ -0,0 +1,80 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+)
+
+func TestProcessEnvironment(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mgr := NewEnvironmentManager()
+	err := mgr.ProcessEnvironment("kind-1", "kind", "/tmp", nil, []string{
+		"CLUSTER=$(cluster-name)",
+		"REGION=${CLOUDTEST_UNDEFINED_REGION:-us-east}",
+		"PRICE=$$5",
+	}, nil)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(mgr.GetProcessedEnv()).Should(gomega.Equal([]string{"CLUSTER=kind-1", "REGION=us-east", "PRICE=$5"}))
+
+	mgr.AddOutput("REGION", "eu-west")
+	mgr.AddOutput("TOKEN", "token")
+	g.Expect(mgr.GetProcessedEnv()).Should(gomega.Equal([]string{"CLUSTER=kind-1", "REGION=eu-west", "PRICE=$5", "TOKEN=token"}))
+	g.Expect(mgr.GetOutputs()).Should(gomega.Equal([]string{"REGION=eu-west", "TOKEN=token"}))
+	g.Expect(mgr.GetArguments()).Should(gomega.HaveKeyWithValue("TOKEN", "token"))
+
+	// Environment and outputs are processed again on next start.
+	err = mgr.ProcessEnvironment("kind-1", "kind", "/tmp", nil, []string{"CLUSTER=$(cluster-name)"}, nil)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(mgr.GetProcessedEnv()).Should(gomega.Equal([]string{"CLUSTER=kind-1"}))
+	g.Expect(mgr.GetOutputs()).Should(gomega.BeEmpty())
+
+	err = mgr.ProcessEnvironment("kind-1", "kind", "/tmp", nil, []string{"A=${CLOUDTEST_UNDEFINED_TOKEN:?token is required}"}, nil)
+	g.Expect(err).Should(gomega.MatchError(`env A: failed to substitute "${CLOUDTEST_UNDEFINED_TOKEN:?token is required}": ` +
+		`CLOUDTEST_UNDEFINED_TOKEN: token is required`))
+}
+
+func TestProcessEnvironmentEnvFiles(t *testing.T) {
+	g := gomega.NewWithT(t)
+	dir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	g.Expect(err).Should(gomega.BeNil())
+	defer func() { _ = os.RemoveAll(dir) }()
+	g.Expect(ioutil.WriteFile(path.Join(dir, "kind.env"), []byte("# Cluster access\n"+
+		"export TOKEN=\"s3cr3t-token\"\n"+
+		"REGION=${CLOUDTEST_UNDEFINED_REGION:-us-east}\n"), 0600)).Should(gomega.BeNil())
+
+	mgr := NewEnvironmentManager()
+	err = mgr.ProcessEnvironment("kind-1", "kind", dir, config.EnvFiles{
+		{Path: "$(tempdir)/kind.env"},
+		{Path: path.Join(dir, "local.env"), Optional: true},
+	}, []string{"AUTH=Bearer ${TOKEN}", "REGION=${REGION}-1"}, nil)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(mgr.GetProcessedEnv()).Should(gomega.Equal([]string{"TOKEN=s3cr3t-token", "REGION=us-east-1", "AUTH=Bearer s3cr3t-token"}))
+	g.Expect(mgr.GetSecrets()).Should(gomega.Equal([]string{"s3cr3t-token", "us-east"}))
+
+	err = mgr.ProcessEnvironment("kind-1", "kind", dir, config.EnvFiles{{Path: path.Join(dir, "local.env")}}, nil, nil)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+	g.Expect(err.Error()).Should(gomega.HavePrefix("env-file " + path.Join(dir, "local.env") + ": "))
+	g.Expect(mgr.GetSecrets()).Should(gomega.BeEmpty())
+}
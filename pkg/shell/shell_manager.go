@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -20,9 +21,9 @@ type Manager interface {
 	// GetConfigLocation - detect if KUBECONFIG variable is passed and return its value.
 	GetConfigLocation() string
 	// RunCmd - execute a command, operation with extra env
-	RunCmd(context context.Context, operation string, script []string, env []string) (string, error)
+	RunCmd(context context.Context, operation string, script config.Script, env []string) (string, error)
 	// RunRead - execute a command, operation with extra env and read response into variable
-	RunRead(context context.Context, operation string, script []string, env []string) (string, error)
+	RunRead(context context.Context, operation string, script config.Script, env []string) (string, error)
 	// PrintEnv - print environment variables into string
 	PrintEnv(processedEnv []string) string
 	// PrintArgs - print arguments to string
@@ -50,67 +51,103 @@ func NewManager(manager execmanager.ExecutionManager, id string, config *config.
 }
 
 // RunCmd -  command in context and add appropriate execution output file.
-func (si *shellInterface) RunCmd(context context.Context, operation string, script, env []string) (string, error) {
+func (si *shellInterface) RunCmd(context context.Context, operation string, script config.Script, env []string) (string, error) {
 	fileName, _, err := si.runCmd(context, operation, script, env, false)
 	return fileName, err
 }
 
 // Run command in context and add appropriate execution output file.
-func (si *shellInterface) RunRead(context context.Context, operation string, script, env []string) (string, error) {
+func (si *shellInterface) RunRead(context context.Context, operation string, script config.Script, env []string) (string, error) {
 	_, response, err := si.runCmd(context, operation, script, env, true)
 	return response, err
 }
-func (si *shellInterface) runCmd(context context.Context, operation string, script, env []string, returnResult bool) (string, string, error) {
-	fileName, fileRef, err := si.manager.OpenFile(si.id, operation)
+func (si *shellInterface) runCmd(ctx context.Context, operation string, script config.Script, env []string, returnResult bool) (string, string, error) {
+	fileName := ""
+	finalOut := ""
+	// Processed environment is not printed.
+	processed := len(si.processedEnv)
+	err := RunSteps(ctx, script, &StepOptions{
+		Name:      operation,
+		Env:       append(append([]string{}, si.processedEnv...), env...),
+		Args:      si.finalArgs,
+		SetOutput: si.AddOutput,
+		OpenLog: func(step string) (io.WriteCloser, error) {
+			// Every step of script has its own log file.
+			name := operation
+			if step != "" {
+				name += "-" + step
+			}
+			var fileRef *os.File
+			var err error
+			fileName, fileRef, err = si.manager.OpenFile(si.id, name)
+			if err != nil {
+				logrus.Errorf("failed to %s system for testing of cluster %s %v", operation, si.config.Name, err)
+				return nil, err
+			}
+			return fileRef, nil
+		},
+	}, func(stepCtx context.Context, script string, cmdEnv []string, writer *bufio.Writer) error {
+		stdOut, err := si.runScript(stepCtx, operation, script, cmdEnv, si.PrintEnv(cmdEnv[processed:]), writer, returnResult)
+		finalOut += stdOut
+		return err
+	})
 	if err != nil {
-		logrus.Errorf("failed to %s system for testing of cluster %s %v", operation, si.config.Name, err)
 		return fileName, "", err
 	}
+	return fileName, finalOut, nil
+}
 
-	defer func() { _ = fileRef.Close() }()
+func (si *shellInterface) runScript(context context.Context, operation, script string, cmdEnv []string, printableEnv string, writer *bufio.Writer, returnResult bool) (string, error) {
+	logger := func(s string) {
+		// logrus.Infof("%s: %s -> %v", si.id, operation, s)
+	}
+
+	if si.config.Shell == utils.ShellBash {
+		_, _ = writer.WriteString(fmt.Sprintf("%s: %v\nENV={\n%v\n}\n", operation, script, printableEnv))
+		_ = writer.Flush()
+		logrus.Infof("%s: %s => %s script", operation, si.id, si.config.Shell)
 
-	writer := bufio.NewWriter(fileRef)
+		stdOut, err := utils.RunScript(context, script, "", logger, writer, cmdEnv, si.finalArgs, returnResult)
+		if err != nil {
+			_, _ = writer.WriteString(fmt.Sprintf("error running script: %v\n", err))
+			_ = writer.Flush()
+			return "", err
+		}
+		return stdOut, nil
+	}
 
 	finalOut := ""
-	for _, cmd := range script {
+	for _, cmd := range utils.ParseScript(script) {
 		if strings.TrimSpace(cmd) == "" {
 			continue
 		}
 
-		cmdEnv := append(si.processedEnv, env...)
-		printableEnv := si.PrintEnv(env)
-
 		_, _ = writer.WriteString(fmt.Sprintf("%s: %v\nENV={\n%v\n}\n", operation, cmd, printableEnv))
 		_ = writer.Flush()
 
 		logrus.Infof("%s: %s => %s", operation, si.id, cmd)
 
-		logger := func(s string) {
-			// logrus.Infof("%s: %s -> %v", si.id, operation, s)
-		}
 		stdOut, err := utils.RunCommand(context, cmd, "", logger, writer, cmdEnv, si.finalArgs, returnResult)
 		if err != nil {
 			_, _ = writer.WriteString(fmt.Sprintf("error running command: %v\n", err))
 			_ = writer.Flush()
-			return fileName, "", err
+			return "", err
 		}
 		if returnResult {
 			finalOut += stdOut
 		}
 	}
-	return fileName, finalOut, nil
+	return finalOut, nil
 }
+
 func (si *shellInterface) PrintEnv(processedEnv []string) string {
 	printableEnv := strings.Builder{}
 	for _, cmdEnvValue := range processedEnv {
 		varName, varValue, _ := utils.ParseVariable(cmdEnvValue)
 
 		if !si.params.NoMaskParameters {
-			// We need to check if value contains or not some of check env variables and replace their values for safity
-			for _, ce := range si.config.EnvCheck {
-				envValue := os.Getenv(ce)
-				varValue = strings.Replace(varValue, envValue, "****", -1)
-			}
+			// We need to check if value contains or not some of check env variables or env file values and replace them for safity
+			varValue = utils.MaskValues(utils.MaskEnvValues(varValue, si.config.EnvCheck), si.secrets)
 		}
 		_, _ = printableEnv.WriteString(fmt.Sprintf("%s=%s\n", varName, varValue))
 	}
@@ -123,11 +160,8 @@ func (si *shellInterface) PrintArgs() string {
 
 	for varName, varValue := range si.finalArgs {
 		if !si.params.NoMaskParameters {
-			// We need to check if value contains or not some of check env variables and replace their values for safity
-			for _, ce := range si.config.EnvCheck {
-				envValue := os.Getenv(ce)
-				varValue = strings.Replace(varValue, envValue, "****", -1)
-			}
+			// We need to check if value contains or not some of check env variables or env file values and replace them for safity
+			varValue = utils.MaskValues(utils.MaskEnvValues(varValue, si.config.EnvCheck), si.secrets)
 		}
 		_, _ = printableEnv.WriteString(fmt.Sprintf("%s=%s\n", varName, varValue))
 	}
@@ -0,0 +1,95 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// stepOutput - captures output of step and provides a file step could write its outputs to.
+type stepOutput struct {
+	fileName string
+	captured strings.Builder
+}
+
+func newStepOutput() (*stepOutput, error) {
+	file, err := ioutil.TempFile("", "cloudtest-output-")
+	if err != nil {
+		return nil, err
+	}
+	_ = file.Close()
+	return &stepOutput{fileName: file.Name()}, nil
+}
+
+func (o *stepOutput) Write(p []byte) (int, error) {
+	return o.captured.Write(p)
+}
+
+// Reset - forget output and outputs file content of previous attempt of step.
+func (o *stepOutput) Reset() error {
+	o.captured.Reset()
+	return ioutil.WriteFile(o.fileName, nil, 0600)
+}
+
+// Close - remove outputs file.
+func (o *stepOutput) Close() {
+	_ = os.Remove(o.fileName)
+}
+
+// Values - return values of outputs, values written to outputs file take precedence over KEY=VALUE lines of output,
+// last value is used if output is set few times.
+func (o *stepOutput) Values(names []string) (map[string]string, error) {
+	values := map[string]string{}
+	parseOutputs(o.captured.String(), names, values)
+	content, err := ioutil.ReadFile(o.fileName)
+	if err != nil {
+		return nil, err
+	}
+	parseOutputs(string(content), names, values)
+	for _, name := range names {
+		if _, ok := values[name]; !ok {
+			return nil, errors.Errorf("output %s is not set", name)
+		}
+	}
+	return values, nil
+}
+
+// parseOutputs - find KEY=VALUE lines of outputs with passed names, environment printed by shell manager as
+// ENV={...} block is skipped.
+func parseOutputs(content string, names []string, values map[string]string) {
+	envBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		switch {
+		case line == "ENV={":
+			envBlock = true
+			continue
+		case envBlock:
+			envBlock = line != "}"
+			continue
+		}
+		for _, name := range names {
+			if strings.HasPrefix(line, name+"=") {
+				values[name] = strings.TrimPrefix(line, name+"=")
+			}
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+)
+
+// OutputEnv - an environment variable with a name of file step could write its outputs to, as KEY=VALUE lines.
+const OutputEnv = "CLOUDTEST_OUTPUT"
+
+// StepFunc - run a script of step with step environment variables added to env, output is written to writer.
+type StepFunc func(ctx context.Context, script string, env []string, writer *bufio.Writer) error
+
+// StepOptions - options of script steps execution.
+type StepOptions struct {
+	Name    string            // A script name, used in output
+	Timeout time.Duration     // A timeout of steps without own timeout, no timeout if zero
+	Env     []string          // Environment variables of script, variables of step are added to them
+	Args    map[string]string // Arguments $(arg) of step environment variables are substituted with
+	Out     *bufio.Writer     // Output of all steps, if nil output is written to step log only
+	// OpenLog - open a log file of step, step is an empty string for a plain script. No log file is written if nil
+	// file is returned.
+	OpenLog func(step string) (io.WriteCloser, error)
+	// SetOutput - called for every output captured by a step, outputs are also added to environment of next steps.
+	SetOutput func(key, value string)
+}
+
+// RunSteps - run steps of script one by one. A step is executed with its timeout and environment, and is retried on
+// failure with a delay. Script is stopped by a failed step unless step could continue on error.
+func RunSteps(ctx context.Context, script config.Script, options *StepOptions, run StepFunc) error {
+	env := options.Env
+	for idx, step := range script {
+		name := StepName(script, idx)
+		outputs, err := runStep(ctx, step, name, env, options, run)
+		if err != nil {
+			if !step.ContinueOnError {
+				return err
+			}
+			logrus.Warnf("%s: step %s is failed, continue on error: %v", options.Name, name, err)
+		}
+		for _, key := range step.Outputs {
+			if value, ok := outputs[key]; ok {
+				env = append(env[:len(env):len(env)], fmt.Sprintf("%s=%s", key, value))
+				if options.SetOutput != nil {
+					options.SetOutput(key, value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// StepName - return a name of step, a step without name is named by its number. Steps of plain script have no names.
+func StepName(script config.Script, idx int) string {
+	if script.IsPlain() {
+		return ""
+	}
+	if script[idx].Name != "" {
+		return script[idx].Name
+	}
+	return fmt.Sprintf("step-%d", idx+1)
+}
+
+func runStep(ctx context.Context, step *config.ScriptStep, name string, env []string, options *StepOptions, run StepFunc) (map[string]string, error) {
+	var log io.WriteCloser
+	if options.OpenLog != nil {
+		var err error
+		if log, err = options.OpenLog(name); err != nil {
+			return nil, err
+		}
+	}
+	if log != nil {
+		defer func() { _ = log.Close() }()
+	}
+	writer := options.Out
+	switch {
+	case log != nil && writer != nil:
+		writer = bufio.NewWriter(&teeWriter{out: writer, log: log})
+	case log != nil:
+		writer = bufio.NewWriter(log)
+	case writer == nil:
+		writer = bufio.NewWriter(&teeWriter{})
+	}
+
+	env, err := stepEnv(env, step.Env, options.Args)
+	if err != nil {
+		return nil, errors.Wrapf(err, "step %s", name)
+	}
+	var output *stepOutput
+	if len(step.Outputs) > 0 {
+		if output, err = newStepOutput(); err != nil {
+			return nil, errors.Wrapf(err, "step %s", name)
+		}
+		defer output.Close()
+		env = append(env[:len(env):len(env)], fmt.Sprintf("%s=%s", OutputEnv, output.fileName))
+		writer = bufio.NewWriter(&teeWriter{out: writer, log: output})
+	}
+	timeout := step.Timeout.Duration()
+	if timeout == 0 {
+		timeout = options.Timeout
+	}
+	for attempt := 0; ; attempt++ {
+		if name != "" {
+			_, _ = writer.WriteString(fmt.Sprintf(">>>>>>Step %s of %s, attempt %d of %d<<<<<<\n", name, options.Name, attempt+1, step.Retries+1))
+			_ = writer.Flush()
+		}
+		if output != nil {
+			if err = output.Reset(); err != nil {
+				break
+			}
+		}
+		stepCtx, cancel := ctx, func() {}
+		if timeout > 0 {
+			stepCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		err = run(stepCtx, step.Run, env, writer)
+		if err != nil && stepCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+			err = errors.Wrapf(err, "timeout %v", timeout)
+		}
+		cancel()
+		if err == nil || attempt >= step.Retries || ctx.Err() != nil {
+			break
+		}
+		logrus.Warnf("%s: step %s is failed, retry in %v: %v", options.Name, name, step.RetryDelay, err)
+		select {
+		case <-ctx.Done():
+		case <-time.After(step.RetryDelay.Duration()):
+		}
+	}
+	var outputs map[string]string
+	if err == nil && output != nil {
+		outputs, err = output.Values(step.Outputs)
+	}
+	if err != nil && name != "" {
+		err = errors.Wrapf(err, "step %s", name)
+	}
+	if err != nil && step.ContinueOnError {
+		_, _ = writer.WriteString(fmt.Sprintf("%v, continue on error\n", err))
+		_ = writer.Flush()
+	}
+	return outputs, err
+}
+
+// stepEnv - add step environment variables to env, $(arg) and ${VAR} in values are substituted.
+func stepEnv(env, stepEnv []string, args map[string]string) ([]string, error) {
+	if len(stepEnv) == 0 {
+		return env, nil
+	}
+	vars := map[string]string{}
+	for _, k := range append(os.Environ(), env...) {
+		key, value, err := utils.ParseVariable(k)
+		if err != nil {
+			return nil, err
+		}
+		vars[key] = value
+	}
+	result := append([]string{}, env...)
+	for _, k := range stepEnv {
+		key, value, err := utils.ParseVariable(k)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = utils.SubstituteVariable(value, vars, args); err != nil {
+			return nil, errors.Wrapf(err, "env %s", key)
+		}
+		vars[key] = value
+		result = append(result, fmt.Sprintf("%s=%s", key, value))
+	}
+	return result, nil
+}
+
+// teeWriter - write to both output and log, output is flushed on every write. Output and log are optional.
+type teeWriter struct {
+	out *bufio.Writer
+	log io.Writer
+}
+
+func (w *teeWriter) Write(p []byte) (int, error) {
+	if w.log != nil {
+		if _, err := w.log.Write(p); err != nil {
+			return 0, err
+		}
+	}
+	if w.out != nil {
+		if _, err := w.out.Write(p); err != nil {
+			return 0, err
+		}
+		return len(p), w.out.Flush()
+	}
+	return len(p), nil
+}
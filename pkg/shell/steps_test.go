@@ -0,0 +1,156 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+)
+
+type stepLog struct {
+	strings.Builder
+}
+
+func (l *stepLog) Close() error {
+	return nil
+}
+
+func TestRunSteps(t *testing.T) {
+	g := gomega.NewWithT(t)
+	script := config.Script{
+		{Name: "flaky", Run: "apply", Retries: 2, RetryDelay: config.Duration(time.Millisecond)},
+		{Run: "check", ContinueOnError: true, Env: []string{"NS=$(namespace)-${PREFIX}"}},
+		{Name: "last", Run: "last"},
+	}
+	attempts := map[string]int{}
+	var stepEnv []string
+	logs := map[string]*stepLog{}
+	output := &strings.Builder{}
+	err := RunSteps(context.Background(), script, &StepOptions{
+		Name: "start",
+		Env:  []string{"PREFIX=cloud"},
+		Args: map[string]string{"namespace": "nsm"},
+		Out:  bufio.NewWriter(output),
+		OpenLog: func(step string) (io.WriteCloser, error) {
+			logs[step] = &stepLog{}
+			return logs[step], nil
+		},
+	}, func(ctx context.Context, script string, env []string, writer *bufio.Writer) error {
+		attempts[script]++
+		_, _ = writer.WriteString(script + "\n")
+		_ = writer.Flush()
+		switch {
+		case script == "apply" && attempts[script] < 3:
+			return errors.New("apply failed")
+		case script == "check":
+			stepEnv = env
+			return errors.New("check failed")
+		}
+		return nil
+	})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(attempts).Should(gomega.Equal(map[string]int{"apply": 3, "check": 1, "last": 1}))
+	g.Expect(stepEnv).Should(gomega.Equal([]string{"PREFIX=cloud", "NS=nsm-cloud"}))
+	g.Expect(logs).Should(gomega.HaveLen(3))
+	g.Expect(logs["flaky"].String()).Should(gomega.Equal(">>>>>>Step flaky of start, attempt 1 of 3<<<<<<\napply\n" +
+		">>>>>>Step flaky of start, attempt 2 of 3<<<<<<\napply\n>>>>>>Step flaky of start, attempt 3 of 3<<<<<<\napply\n"))
+	g.Expect(logs["step-2"].String()).Should(gomega.ContainSubstring("step step-2: check failed, continue on error"))
+	g.Expect(output.String()).Should(gomega.HavePrefix(logs["flaky"].String() + logs["step-2"].String()))
+}
+
+func TestRunStepsStopsOnFailure(t *testing.T) {
+	g := gomega.NewWithT(t)
+	var runs []string
+	err := RunSteps(context.Background(), config.Script{
+		{Name: "first", Run: "first", Retries: 1},
+		{Name: "second", Run: "second"},
+	}, &StepOptions{Name: "stop"}, func(ctx context.Context, script string, env []string, writer *bufio.Writer) error {
+		runs = append(runs, script)
+		return errors.New("failed")
+	})
+	g.Expect(err).Should(gomega.MatchError("step first: failed"))
+	g.Expect(runs).Should(gomega.Equal([]string{"first", "first"}))
+}
+
+func TestRunStepsTimeout(t *testing.T) {
+	g := gomega.NewWithT(t)
+	var deadlines []time.Duration
+	run := func(ctx context.Context, script string, env []string, writer *bufio.Writer) error {
+		deadline, _ := ctx.Deadline()
+		deadlines = append(deadlines, time.Until(deadline).Round(time.Minute))
+		if script == "wait" {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}
+	err := RunSteps(context.Background(), config.Script{
+		{Run: "default"},
+		{Run: "wait", Timeout: config.Duration(10 * time.Millisecond)},
+	}, &StepOptions{Name: "install", Timeout: 3 * time.Minute}, run)
+	g.Expect(err).Should(gomega.MatchError("step step-2: timeout 10ms: context deadline exceeded"))
+	g.Expect(deadlines).Should(gomega.Equal([]time.Duration{3 * time.Minute, 0}))
+
+	// A plain script has no step name.
+	err = RunSteps(context.Background(), config.NewScript("wait"), &StepOptions{Timeout: 10 * time.Millisecond}, run)
+	g.Expect(err).Should(gomega.MatchError("timeout 10ms: context deadline exceeded"))
+}
+
+func TestRunStepsOutputs(t *testing.T) {
+	g := gomega.NewWithT(t)
+	outputs := map[string]string{}
+	var envs [][]string
+	err := RunSteps(context.Background(), config.Script{
+		{Name: "create", Run: "create", Outputs: []string{"CLUSTER", "ADDRESS"}},
+		{Name: "use", Run: "use"},
+	}, &StepOptions{
+		Name:      "start",
+		Env:       []string{"A=1"},
+		SetOutput: func(key, value string) { outputs[key] = value },
+	}, func(ctx context.Context, script string, env []string, writer *bufio.Writer) error {
+		envs = append(envs, env)
+		if script == "create" {
+			// Outputs file takes precedence, printed environment is ignored.
+			_, _ = writer.WriteString("ENV={\nCLUSTER=env\n}\nCLUSTER=kind-1\nADDRESS=stdout\n")
+			_ = writer.Flush()
+			file := env[len(env)-1]
+			g.Expect(file).Should(gomega.HavePrefix(OutputEnv + "="))
+			g.Expect(ioutil.WriteFile(strings.TrimPrefix(file, OutputEnv+"="), []byte("ADDRESS=10.0.0.1\nOTHER=1\n"), 0600)).
+				Should(gomega.BeNil())
+		}
+		return nil
+	})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(outputs).Should(gomega.Equal(map[string]string{"CLUSTER": "kind-1", "ADDRESS": "10.0.0.1"}))
+	g.Expect(envs[1]).Should(gomega.Equal([]string{"A=1", "CLUSTER=kind-1", "ADDRESS=10.0.0.1"}))
+
+	err = RunSteps(context.Background(), config.Script{{Run: "create", Outputs: []string{"CLUSTER"}}},
+		&StepOptions{Name: "start"}, func(ctx context.Context, script string, env []string, writer *bufio.Writer) error {
+			return nil
+		})
+	g.Expect(err).Should(gomega.MatchError("step step-1: output CLUSTER is not set"))
+}
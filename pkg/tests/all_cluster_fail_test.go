@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -18,7 +19,7 @@ func TestClusterInstancesFailed(t *testing.T) {
 
 	testConfig := config.NewCloudTestConfig()
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -27,12 +28,12 @@ func TestClusterInstancesFailed(t *testing.T) {
 	testConfig.ConfigRoot = tmpDir
 	createProvider(testConfig, "a_provider")
 	failedP := createProvider(testConfig, "b_provider")
-	failedP.Scripts["start"] = "echo starting\nexit 2"
+	failedP.Scripts["start"] = config.NewScript("echo starting\nexit 2")
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:        "simple",
-		Timeout:     15,
-		PackageRoot: "./sample",
+		Timeout:     config.Duration(15 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
 	})
 
 	testConfig.Reporting.JUnitReportFile = JunitReport
@@ -65,7 +66,7 @@ func TestClusterInstancesFailedSpecificTestList(t *testing.T) {
 
 	testConfig := &config.CloudTestConfig{}
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -76,8 +77,8 @@ func TestClusterInstancesFailedSpecificTestList(t *testing.T) {
 
 	testConfig.Executions = []*config.Execution{{
 		Name:        "simple",
-		Timeout:     2,
-		PackageRoot: "./sample",
+		Timeout:     config.Duration(2 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
 		Source: config.ExecutionSource{
 			Tests: []string{"TestPass", "TestTimeout", "TestFail"},
 		},
@@ -100,7 +101,7 @@ func TestClusterInstancesOnFailGoRunner(t *testing.T) {
 
 	testConfig := config.NewCloudTestConfig()
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -109,13 +110,13 @@ func TestClusterInstancesOnFailGoRunner(t *testing.T) {
 	testConfig.ConfigRoot = tmpDir
 	createProvider(testConfig, "a_provider")
 	failedP := createProvider(testConfig, "b_provider")
-	failedP.Scripts["start"] = "echo starting\nexit 2"
+	failedP.Scripts["start"] = config.NewScript("echo starting\nexit 2")
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:        "simple",
-		Timeout:     15,
-		PackageRoot: "./sample",
-		OnFail:      `echo >>>Running on fail script<<<`,
+		Timeout:     config.Duration(15 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
+		OnFail:      config.NewScript(`echo >>>Running on fail script<<<`),
 	})
 
 	testConfig.Reporting.JUnitReportFile = JunitReport
@@ -161,7 +162,7 @@ func TestClusterInstancesOnFailShellRunner(t *testing.T) {
 
 	testConfig := config.NewCloudTestConfig()
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -171,18 +172,18 @@ func TestClusterInstancesOnFailShellRunner(t *testing.T) {
 	createProvider(testConfig, "a_provider")
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:    "pass",
-		Timeout: 15,
+		Timeout: config.Duration(15 * time.Second),
 		Kind:    "shell",
-		Run:     "echo pass",
-		OnFail:  `echo >>>Running on fail script<<<`,
+		Run:     config.NewScript("echo pass"),
+		OnFail:  config.NewScript(`echo >>>Running on fail script<<<`),
 	})
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:    "fail",
-		Timeout: 15,
+		Timeout: config.Duration(15 * time.Second),
 		Kind:    "shell",
-		Run:     "make_all_happy()",
+		Run:     config.NewScript("make_all_happy()"),
 		Env:     []string{"name=$(test-name)"},
-		OnFail:  `echo >>>Running on fail script name=${name}<<<`,
+		OnFail:  config.NewScript(`echo >>>Running on fail script name=${name}<<<`),
 	})
 	testConfig.Reporting.JUnitReportFile = JunitReport
 
@@ -208,7 +209,7 @@ func TestClusterInstancesOnFailShellRunnerInterdomain(t *testing.T) {
 
 	testConfig := config.NewCloudTestConfig()
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -216,26 +217,26 @@ func TestClusterInstancesOnFailShellRunnerInterdomain(t *testing.T) {
 
 	testConfig.ConfigRoot = tmpDir
 	ap := createProvider(testConfig, "a_provider")
-	ap.Scripts["config"] = "echo ./.tests/config.a"
+	ap.Scripts["config"] = config.NewScript("echo ./.tests/config.a")
 	bp := createProvider(testConfig, "b_provider")
-	bp.Scripts["config"] = "echo ./.tests/config.b"
+	bp.Scripts["config"] = config.NewScript("echo ./.tests/config.b")
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:            "pass",
-		Timeout:         15,
+		Timeout:         config.Duration(15 * time.Second),
 		ClusterCount:    2,
 		ClusterSelector: []string{"a_provider", "b_provider"},
 		Kind:            "shell",
-		Run:             "echo pass",
-		OnFail:          `echo >>>Running on fail script with ${KUBECONFIG} <<<`,
+		Run:             config.NewScript("echo pass"),
+		OnFail:          config.NewScript(`echo >>>Running on fail script with ${KUBECONFIG} <<<`),
 	})
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:            "fail",
-		Timeout:         15,
+		Timeout:         config.Duration(15 * time.Second),
 		ClusterCount:    2,
 		ClusterSelector: []string{"a_provider", "b_provider"},
 		Kind:            "shell",
-		Run:             "make_all_happy()",
-		OnFail:          `echo >>>Running on fail script with ${KUBECONFIG} <<<`,
+		Run:             config.NewScript("make_all_happy()"),
+		OnFail:          config.NewScript(`echo >>>Running on fail script with ${KUBECONFIG} <<<`),
 	})
 	testConfig.Reporting.JUnitReportFile = JunitReport
 
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/reporting"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGoTestAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	logKeeper := utils.NewLogKeeper()
+	defer logKeeper.Stop()
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = config.Duration(300 * time.Second)
+	testConfig.RetestConfig = config.RetestConfig{
+		Patterns:     []string{"#Please_RETEST#"},
+		RestartCount: 3,
+	}
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	createProvider(testConfig, "a_provider").Instances = 1
+	createProvider(testConfig, "b_provider").Instances = 1
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:            "annotations",
+		Timeout:         config.Duration(60 * time.Second),
+		PackageRoot:     config.StringList{"./sample/annotations"},
+		ClusterSelector: []string{"a_provider"},
+	})
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err.Error()).To(Equal("there is failed tests 2"))
+	g.Expect(report).NotTo(BeNil())
+
+	// Test cases by cluster suite and test names.
+	testCases := map[string]*reporting.TestCase{}
+	for _, clusterSuite := range report.Suites[0].Suites[0].Suites {
+		for _, testCase := range clusterSuite.TestCases {
+			testCases[clusterSuite.Name+"/"+testCase.Name] = testCase
+		}
+	}
+	g.Expect(len(testCases)).To(Equal(3))
+	g.Expect(testCases["a_provider/TestTimeout"].Failure).NotTo(BeNil())
+	g.Expect(testCases["b_provider/TestProviders"].Failure).To(BeNil())
+	g.Expect(testCases["a_provider/TestRetry"].Failure).NotTo(BeNil())
+
+	g.Expect(logKeeper.MessageCount("Re schedule task TestRetry reason: rerun-request")).To(Equal(1))
+	logKeeper.CheckMessagesOrder(t, []string{
+		"Test TestRetry retry count 1 exceed: err",
+	})
+}
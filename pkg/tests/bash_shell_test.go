@@ -0,0 +1,108 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestBashShellScripts(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = config.Duration(300 * time.Second)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	provider := createProvider(testConfig, "a_provider")
+	provider.Instances = 1
+	provider.Shell = utils.ShellBash
+	provider.Scripts["start"] = config.NewScript(`cd /
+export STARTED="started in"
+echo "${STARTED} $(pwd)" | tr a-z A-Z`)
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:    "pass",
+		Kind:    "shell",
+		Shell:   utils.ShellBash,
+		Timeout: config.Duration(15 * time.Second),
+		Env:     []string{"NAME=pass"},
+		Run: config.NewScript(`for i in 1 2; do
+  echo "line $i of ${NAME}"
+done | grep "line 2"
+cat <<EOF
+heredoc
+EOF`),
+	}, &config.Execution{
+		Name:    "fail",
+		Kind:    "shell",
+		Shell:   utils.ShellBash,
+		Timeout: config.Duration(15 * time.Second),
+		Run: config.NewScript(`false | cat
+echo not reached`),
+		OnFail: config.NewScript(`if true; then
+  echo ">>>on fail in bash<<<"
+fi`),
+	})
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err.Error()).To(Equal("there is failed tests 1"))
+	g.Expect(report).NotTo(BeNil())
+
+	g.Expect(len(report.Suites[0].Suites)).To(Equal(2))
+	for _, execSuite := range report.Suites[0].Suites {
+		testCase := execSuite.Suites[0].TestCases[0]
+		if execSuite.Name == "pass" {
+			g.Expect(testCase.Failure).To(BeNil())
+			continue
+		}
+		g.Expect(testCase.Failure).NotTo(BeNil())
+		g.Expect(testCase.Failure.Contents).To(ContainSubstring(">>>>>>Running: false | cat:<<<<<<"))
+		g.Expect(testCase.Failure.Contents).NotTo(ContainSubstring(">>>>>>Running: echo not reached"))
+		g.Expect(testCase.Failure.Contents).To(ContainSubstring(">>>on fail in bash<<<"))
+	}
+
+	var logs string
+	files, err := ioutil.ReadDir(path.Join(tmpDir, provider.Name+"-1"))
+	g.Expect(err).To(BeNil())
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		content, err := ioutil.ReadFile(path.Join(tmpDir, provider.Name+"-1", f.Name()))
+		g.Expect(err).To(BeNil())
+		logs += string(content)
+	}
+	g.Expect(logs).To(ContainSubstring("STARTED IN /\n"))
+	g.Expect(logs).To(ContainSubstring("line 2 of pass\n"))
+	g.Expect(logs).NotTo(ContainSubstring("line 1 of"))
+	g.Expect(logs).To(ContainSubstring("heredoc\n>>>>>>Done: cat <<EOF<<<<<<"))
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/reporting"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestBuildFailureReportedOnce(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = config.Duration(300 * time.Second)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	p := createProvider(testConfig, "a_provider")
+	p.Instances = 1
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "broken",
+		Timeout:     config.Duration(15 * time.Second),
+		PackageRoot: config.StringList{"./sample/broken"},
+		Source: config.ExecutionSource{
+			Tags: []string{"broken"},
+		},
+	}, &config.Execution{
+		Name:        "packages",
+		Timeout:     config.Duration(15 * time.Second),
+		PackageRoot: config.StringList{"./sample/packages/..."},
+	})
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err.Error()).To(Equal("there is failed tests 1"))
+	g.Expect(report).NotTo(BeNil())
+
+	suites := map[string]*reporting.Suite{}
+	for _, suite := range report.Suites[0].Suites {
+		suites[suite.Name] = suite
+	}
+	g.Expect(suites["broken"]).NotTo(BeNil())
+	g.Expect(suites["broken"].Failures).To(Equal(1))
+	g.Expect(suites["broken"].Suites).To(BeEmpty())
+	g.Expect(len(suites["broken"].TestCases)).To(Equal(1))
+
+	buildCase := suites["broken"].TestCases[0]
+	g.Expect(buildCase.Name).To(Equal("Build-github.com/denis-tingajkin/cloudtest/pkg/tests/sample/broken"))
+	g.Expect(buildCase.Failure).NotTo(BeNil())
+	g.Expect(buildCase.Failure.Contents).To(ContainSubstring("undefinedValue"))
+
+	g.Expect(suites["packages"]).NotTo(BeNil())
+	g.Expect(suites["packages"].Failures).To(Equal(0))
+	g.Expect(suites["packages"].Tests).To(Equal(2))
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/denis-tingajkin/cloudtest/pkg/commands"
 	"github.com/denis-tingajkin/cloudtest/pkg/config"
@@ -34,7 +35,7 @@ func TestAfterWorksCorrectly(t *testing.T) {
 
 	testConfig := &config.CloudTestConfig{}
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -42,18 +43,18 @@ func TestAfterWorksCorrectly(t *testing.T) {
 
 	testConfig.ConfigRoot = tmpDir
 	provider := &config.ClusterProviderConfig{
-		Timeout:    100,
+		Timeout:    config.Duration(100 * time.Second),
 		Name:       "provider",
 		NodeCount:  1,
 		Kind:       "shell",
 		RetryCount: 1,
 		Instances:  1,
-		Scripts: map[string]string{
-			"config":  "echo ./.tests/config",
-			"start":   "echo started",
-			"prepare": "echo prepared",
-			"install": "echo installed",
-			"stop":    "echo stopped",
+		Scripts: map[string]config.Script{
+			"config":  config.NewScript("echo ./.tests/config"),
+			"start":   config.NewScript("echo started"),
+			"prepare": config.NewScript("echo prepared"),
+			"install": config.NewScript("echo installed"),
+			"stop":    config.NewScript("echo stopped"),
 		},
 		Enabled: true,
 	}
@@ -61,17 +62,17 @@ func TestAfterWorksCorrectly(t *testing.T) {
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:    "test1",
-		Timeout: 15,
+		Timeout: config.Duration(15 * time.Second),
 		Kind:    "shell",
-		Run:     "echo first",
+		Run:     config.NewScript("echo first"),
 		Env:     []string{"A=worked", "B=$(test-name)"},
-		After:   "echo ${B} ${A}",
+		After:   config.NewScript("echo ${B} ${A}"),
 	})
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:    "test2",
-		Timeout: 15,
+		Timeout: config.Duration(15 * time.Second),
 		Kind:    "shell",
-		Run:     "echo second",
+		Run:     config.NewScript("echo second"),
 	})
 
 	testConfig.Reporting.JUnitReportFile = JunitReport
@@ -91,7 +92,7 @@ func TestBeforeWorksCorrectly(t *testing.T) {
 
 	testConfig := &config.CloudTestConfig{}
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -99,18 +100,18 @@ func TestBeforeWorksCorrectly(t *testing.T) {
 
 	testConfig.ConfigRoot = tmpDir
 	provider := &config.ClusterProviderConfig{
-		Timeout:    100,
+		Timeout:    config.Duration(100 * time.Second),
 		Name:       "provider",
 		NodeCount:  1,
 		Kind:       "shell",
 		RetryCount: 1,
 		Instances:  1,
-		Scripts: map[string]string{
-			"config":  "echo ./.tests/config",
-			"start":   "echo started",
-			"prepare": "echo prepared",
-			"install": "echo installed",
-			"stop":    "echo stopped",
+		Scripts: map[string]config.Script{
+			"config":  config.NewScript("echo ./.tests/config"),
+			"start":   config.NewScript("echo started"),
+			"prepare": config.NewScript("echo prepared"),
+			"install": config.NewScript("echo installed"),
+			"stop":    config.NewScript("echo stopped"),
 		},
 		Enabled: true,
 	}
@@ -118,17 +119,17 @@ func TestBeforeWorksCorrectly(t *testing.T) {
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:    "test1",
-		Timeout: 15,
+		Timeout: config.Duration(15 * time.Second),
 		Kind:    "shell",
-		Run:     "echo first",
+		Run:     config.NewScript("echo first"),
 		Env:     []string{"A=worked", "B=$(test-name)"},
-		Before:  "echo ${B} ${A}",
+		Before:  config.NewScript("echo ${B} ${A}"),
 	})
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:    "test2",
-		Timeout: 15,
+		Timeout: config.Duration(15 * time.Second),
 		Kind:    "shell",
-		Run:     "echo second",
+		Run:     config.NewScript("echo second"),
 	})
 
 	testConfig.Reporting.JUnitReportFile = JunitReport
@@ -0,0 +1,91 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestEnvFiles(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = config.Duration(300 * time.Second)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	// Configuration root is cleaned on start, so env files are stored separately.
+	envDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-env")
+	defer utils.ClearFolder(envDir, false)
+	g.Expect(err).To(BeNil())
+
+	providerEnv := path.Join(envDir, "provider.env")
+	g.Expect(ioutil.WriteFile(providerEnv, []byte("PROVIDER_TOKEN=provider-s3cr3t\n"), os.ModePerm)).To(BeNil())
+	executionEnv := path.Join(envDir, "execution.env")
+	g.Expect(ioutil.WriteFile(executionEnv, []byte("TEST_TOKEN='test-s3cr3t'\n"), os.ModePerm)).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	provider := createProvider(testConfig, "a_provider")
+	provider.Instances = 1
+	provider.EnvFile = config.EnvFiles{{Path: providerEnv}, {Path: path.Join(envDir, "missing.env"), Optional: true}}
+	provider.Env = append(provider.Env, "AUTH=token ${PROVIDER_TOKEN}")
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:    "env-file",
+		Kind:    "shell",
+		Timeout: config.Duration(15 * time.Second),
+		EnvFile: config.EnvFiles{{Path: executionEnv}},
+		Run:     config.NewScript(`echo "test with ${TEST_TOKEN}"`),
+	})
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err).To(BeNil())
+	g.Expect(report).NotTo(BeNil())
+
+	var logs, environment string
+	files, err := ioutil.ReadDir(path.Join(tmpDir, provider.Name+"-1"))
+	g.Expect(err).To(BeNil())
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		content, err := ioutil.ReadFile(path.Join(tmpDir, provider.Name+"-1", f.Name()))
+		g.Expect(err).To(BeNil())
+		logs += string(content)
+		if strings.HasSuffix(f.Name(), "environment.log") {
+			environment += string(content)
+		}
+	}
+	g.Expect(environment).To(ContainSubstring("PROVIDER_TOKEN=****\n"))
+	g.Expect(environment).To(ContainSubstring("AUTH=token ****\n"))
+	g.Expect(environment).NotTo(ContainSubstring("provider-s3cr3t"))
+	g.Expect(logs).To(ContainSubstring("test with test-s3cr3t\n"))
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/reporting"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestIncludeExcludeFilters(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = config.Duration(300 * time.Second)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	p := createProvider(testConfig, "a_provider")
+	p.Instances = 1
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "filtered",
+		Timeout:     config.Duration(15 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
+		Source: config.ExecutionSource{
+			Include: []string{"^TestPass$", "Fail"},
+			Exclude: []string{"Fail"},
+		},
+	})
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err).To(BeNil())
+	g.Expect(report).NotTo(BeNil())
+
+	testCases := map[string]*reporting.TestCase{}
+	for _, testCase := range report.Suites[0].Suites[0].Suites[0].TestCases {
+		testCases[testCase.Name] = testCase
+	}
+	g.Expect(len(testCases)).To(Equal(3))
+	g.Expect(testCases["TestPass"].SkipMessage).To(BeNil())
+	g.Expect(testCases["TestPass"].Failure).To(BeNil())
+	g.Expect(testCases["TestFail"].SkipMessage.Message).To(Equal(`Excluded by source.exclude "Fail"`))
+	g.Expect(testCases["TestTimeout"].SkipMessage.Message).To(Equal(`Not included by source.include "^TestPass$", "Fail"`))
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestInstanceOverridesAreApplied(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfig := &config.CloudTestConfig{}
+	testConfig.Timeout = config.Duration(300 * time.Second)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	testConfig.Providers = append(testConfig.Providers, &config.ClusterProviderConfig{
+		Timeout:    config.Duration(100 * time.Second),
+		Name:       "provider",
+		NodeCount:  1,
+		Kind:       "shell",
+		RetryCount: 1,
+		Instances:  2,
+		Scripts: map[string]config.Script{
+			"config":  config.NewScript("echo ./.tests/config"),
+			"start":   config.NewScript("echo started"),
+			"prepare": config.NewScript("echo prepared"),
+			"install": config.NewScript("echo installed"),
+			"stop":    config.NewScript("echo stopped"),
+		},
+		Env:     []string{"ZONE=zone-a", "SIZE=small"},
+		Enabled: true,
+		InstanceOverrides: []*config.InstanceOverride{
+			{Index: 2, Env: []string{"ZONE=zone-b"}},
+		},
+	})
+
+	for _, name := range []string{"first", "second"} {
+		testConfig.Executions = append(testConfig.Executions, &config.Execution{
+			Name:    name,
+			Timeout: config.Duration(15 * time.Second),
+			Kind:    "shell",
+			Run:     config.NewScript("sleep 1"),
+		})
+	}
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err).To(BeNil())
+	g.Expect(report).NotTo(BeNil())
+
+	logs := map[string]string{}
+	for _, f := range utils.GetAllFiles(tmpDir) {
+		if strings.HasSuffix(f, "environment.log") {
+			content, err := ioutil.ReadFile(f)
+			g.Expect(err).To(BeNil())
+			logs[filepath.Base(filepath.Dir(f))] = string(content)
+		}
+	}
+	g.Expect(logs["provider-1"]).To(ContainSubstring("ZONE=zone-a\n"))
+	g.Expect(logs["provider-1"]).NotTo(ContainSubstring("Instance overrides"))
+	g.Expect(logs["provider-2"]).To(ContainSubstring("Instance overrides: env ZONE\n"))
+	g.Expect(logs["provider-2"]).To(ContainSubstring("ZONE=zone-b\n"))
+	g.Expect(logs["provider-2"]).To(ContainSubstring("SIZE=small\n"))
+}
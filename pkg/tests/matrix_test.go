@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestMatrixVariantsAreReportedSeparately(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfig := &config.CloudTestConfig{}
+	testConfig.Timeout = config.Duration(300 * time.Second)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	testConfig.Providers = append(testConfig.Providers, &config.ClusterProviderConfig{
+		Timeout:    config.Duration(100 * time.Second),
+		Name:       "provider",
+		NodeCount:  1,
+		Kind:       "shell",
+		RetryCount: 1,
+		Instances:  1,
+		Scripts: map[string]config.Script{
+			"config":  config.NewScript("echo ./.tests/config"),
+			"start":   config.NewScript("echo started"),
+			"prepare": config.NewScript("echo prepared"),
+			"install": config.NewScript("echo installed"),
+			"stop":    config.NewScript("echo stopped"),
+		},
+		Enabled: true,
+	})
+
+	execution := &config.Execution{
+		Name:    "matrix",
+		Timeout: config.Duration(15 * time.Second),
+		Kind:    "shell",
+		Run:     config.NewScript(`test "${IPV6}" = "on"`),
+		Matrix: map[string][]string{
+			"IPV6":     {"on", "off"},
+			"INSECURE": {"true", "false"},
+		},
+		MatrixExclude: []map[string]string{{"INSECURE": "false", "IPV6": "off"}},
+	}
+	testConfig.Executions, err = execution.ExpandMatrix()
+	g.Expect(err).To(BeNil())
+
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err).ShouldNot(BeNil())
+	g.Expect(report).NotTo(BeNil())
+
+	failures := map[string]int{}
+	for _, executionSuite := range report.Suites[0].Suites {
+		failures[executionSuite.Name] = executionSuite.Failures
+	}
+	g.Expect(failures).To(Equal(map[string]int{
+		"matrix[INSECURE=false,IPV6=on]": 0,
+		"matrix[INSECURE=true,IPV6=off]": 1,
+		"matrix[INSECURE=true,IPV6=on]":  0,
+	}))
+}
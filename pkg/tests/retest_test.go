@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -41,7 +42,7 @@ func TestRestartRequest(t *testing.T) {
 			RestartCount: 2,
 		},
 	}
-	testConfig.Timeout = 3000
+	testConfig.Timeout = config.Duration(3000 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -55,8 +56,8 @@ func TestRestartRequest(t *testing.T) {
 		Source: config.ExecutionSource{
 			Tags: []string{"request_restart"},
 		},
-		Timeout:     1500,
-		PackageRoot: "./sample",
+		Timeout:     config.Duration(1500 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
 	})
 
 	testConfig.Reporting.JUnitReportFile = JunitReport
@@ -95,7 +96,7 @@ func TestRestartRetestDestroyCluster(t *testing.T) {
 			WarmupTimeout:  0,
 		},
 	}
-	testConfig.Timeout = 1000
+	testConfig.Timeout = config.Duration(1000 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -111,8 +112,8 @@ func TestRestartRetestDestroyCluster(t *testing.T) {
 		Source: config.ExecutionSource{
 			Tags: []string{"request_restart"},
 		},
-		Timeout:     1500,
-		PackageRoot: "./sample",
+		Timeout:     config.Duration(1500 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
 	})
 
 	testConfig.Reporting.JUnitReportFile = JunitReport
@@ -156,10 +157,10 @@ func TestRestartRequestRestartCluster(t *testing.T) {
 			Patterns:       []string{"#Please_RETEST#"},
 			RestartCount:   3,
 			AllowedRetests: 2,
-			WarmupTimeout:  1,
+			WarmupTimeout:  config.Duration(time.Second),
 		},
 	}
-	testConfig.Timeout = 1000
+	testConfig.Timeout = config.Duration(1000 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -175,8 +176,8 @@ func TestRestartRequestRestartCluster(t *testing.T) {
 		Source: config.ExecutionSource{
 			Tags: []string{"request_restart"},
 		},
-		Timeout:     1500,
-		PackageRoot: "./sample",
+		Timeout:     config.Duration(1500 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
 	})
 
 	testConfig.Reporting.JUnitReportFile = JunitReport
@@ -197,7 +198,7 @@ func TestRestartRequestRestartCluster(t *testing.T) {
 		"Reached a limit of re-tests per cluster instance",
 		"Destroying cluster",
 		"Starting cluster ",
-		"Test TestRequestRestart retry count 3 exceed: err: failed to run go test",
+		"Test TestRequestRestart retry count 3 exceed: err: failed to run go tool test2json",
 	})
 	g.Expect(logKeeper.MessageCount("Re schedule task TestRequestRestart reason: rerun-request")).To(Equal(3))
 }
@@ -215,7 +216,7 @@ func TestRestartRequestSkip(t *testing.T) {
 			RetestFailResult: "skip",
 		},
 	}
-	testConfig.Timeout = 3000
+	testConfig.Timeout = config.Duration(3000 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -229,8 +230,8 @@ func TestRestartRequestSkip(t *testing.T) {
 		Source: config.ExecutionSource{
 			Tags: []string{"request_restart"},
 		},
-		Timeout:     1500,
-		PackageRoot: "./sample",
+		Timeout:     config.Duration(1500 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
 	})
 
 	testConfig.Reporting.JUnitReportFile = JunitReport
@@ -246,11 +247,14 @@ func TestRestartRequestSkip(t *testing.T) {
 	g.Expect(rootSuite.Suites[0].Tests).To(Equal(2))
 	g.Expect(len(rootSuite.Suites[0].Suites[0].TestCases)).To(Equal(2))
 
-	for _, tt := range rootSuite.Suites[0].TestCases {
-		if tt.Name == "_TestRequestRestart" {
-			g.Expect(tt.SkipMessage.Message).To(Equal("Test TestRequestRestart retry count 2 exceed: err: failed to run go test . -test.timeout 50m0s -count 1 --run \"^(TestRequestRestart)\\\\z\" --tags \"request_restart\" --test.v ExitCode: 1"))
+	checked := false
+	for _, tt := range rootSuite.Suites[0].Suites[0].TestCases {
+		if tt.Name == "TestRequestRestart" {
+			checked = true
+			g.Expect(tt.SkipMessage.Message).To(MatchRegexp(`^Test TestRequestRestart retry count 2 exceed: err: failed to run go tool test2json -p \S+ ".*\.test" -test\.v -test\.timeout 25m0s -test\.count 1 -test\.run "\^\(TestRequestRestart\)\\\\z" ExitCode: 1$`))
 		}
 	}
+	g.Expect(checked).To(BeTrue())
 
 	logKeeper.CheckMessagesOrder(t, []string{
 		"Starting TestRequestRestart",
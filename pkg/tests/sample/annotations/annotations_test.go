@@ -0,0 +1,37 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotations
+
+import (
+	"testing"
+	"time"
+)
+
+// cloudtest:timeout=1s
+func TestTimeout(t *testing.T) {
+	time.Sleep(5 * time.Second)
+}
+
+// TestProviders - executed only on b_provider.
+// cloudtest:providers=b_provider
+func TestProviders(t *testing.T) {
+}
+
+// cloudtest:retry=1
+func TestRetry(t *testing.T) {
+	t.Fatal("#Please_RETEST#")
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package results - an example tests of structured go test results
+package results
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSkipped(t *testing.T) {
+	t.Skip("not supported here")
+}
+
+func TestParallel(t *testing.T) {
+	for _, name := range []string{"first", "second"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			for i := 0; i < 3; i++ {
+				t.Logf("output of %s", name)
+				<-time.After(10 * time.Millisecond)
+			}
+			if name == "second" {
+				t.Errorf("failed %s", name)
+			}
+		})
+	}
+}
+
+func TestNested(t *testing.T) {
+	t.Run("parent", func(t *testing.T) {
+		t.Run("child", func(t *testing.T) {
+			t.Skip("skipped child")
+		})
+	})
+}
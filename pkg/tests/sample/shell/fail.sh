@@ -0,0 +1,3 @@
+# An example of failed shell test, executed with bash since it is not executable
+echo "failed"
+exit 1
@@ -0,0 +1,3 @@
+#!/bin/bash
+# cloudtest:cluster-count=2
+echo "passed on ${KUBECONFIG} and ${KUBECONFIG1}"
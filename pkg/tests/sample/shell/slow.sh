@@ -0,0 +1,5 @@
+#!/bin/bash
+# cloudtest:tags=slow
+# cloudtest:timeout=1s
+echo "started"
+sleep 5
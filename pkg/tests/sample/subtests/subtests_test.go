@@ -0,0 +1,52 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package subtests - an example tests with subtests
+package subtests
+
+import (
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	tests := []struct {
+		name string
+		fail bool
+	}{
+		{name: "first"},
+		{name: "second case", fail: true},
+		{name: "third"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.fail {
+				t.Fatalf("%s is failed", tc.name)
+			}
+		})
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	t.Run("alpha", func(t *testing.T) {})
+	t.Run("beta", func(t *testing.T) {
+		t.Run("nested", func(t *testing.T) {})
+	})
+}
+
+func TestPlain(t *testing.T) {
+	t.Logf("no subtests")
+}
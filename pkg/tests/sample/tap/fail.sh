@@ -0,0 +1,8 @@
+#!/bin/bash
+echo "1..3"
+echo "ok 1 - first"
+echo "not ok 2 - second"
+echo "# expected: ready"
+echo "#   actual: pending"
+echo "ok 3 - third"
+exit 0
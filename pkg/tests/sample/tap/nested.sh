@@ -0,0 +1,11 @@
+#!/bin/bash
+echo "TAP version 14"
+echo "# cluster: shell"
+echo "1..2"
+echo "# Subtest: deployment"
+echo "    1..2"
+echo "    ok 1 - pods are created"
+echo "    ok 2 - pods are ready"
+echo "    # all pods are ready"
+echo "ok 1 - deployment"
+echo "ok 2 - cleanup"
@@ -0,0 +1,7 @@
+#!/bin/bash
+echo "1..4"
+echo "ok 1 - cluster is available"
+echo "ok 2 - pods are/running # SKIP no pods in shell cluster"
+echo "not ok 3 - feature is enabled # TODO not implemented"
+echo "# diagnostic of todo point"
+echo "ok 4"
@@ -0,0 +1,81 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestScriptOutputs(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = config.Duration(300 * time.Second)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	provider := createProvider(testConfig, "a_provider")
+	provider.Instances = 1
+	provider.Shell = utils.ShellBash
+	provider.Scripts["start"] = config.Script{
+		{Name: "create", Run: "echo CLUSTER_ADDRESS=10.1.2.3", Outputs: []string{"CLUSTER_ADDRESS"}},
+		{Name: "token", Run: `echo "TOKEN=token-of-${CLUSTER_ADDRESS}" >> "${CLOUDTEST_OUTPUT}"`, Outputs: []string{"TOKEN"}},
+	}
+	provider.Scripts["prepare"] = config.NewScript(`echo "prepare $(CLUSTER_ADDRESS) with ${TOKEN}"`)
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:    "outputs",
+		Kind:    "shell",
+		Shell:   utils.ShellBash,
+		Timeout: config.Duration(15 * time.Second),
+		Before:  config.NewScript(`echo "before with ${TOKEN}"`),
+		Run:     config.NewScript(`echo "test on ${CLUSTER_ADDRESS} with ${TOKEN}"`),
+	})
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err).To(BeNil())
+	g.Expect(report).NotTo(BeNil())
+
+	var logs string
+	files, err := ioutil.ReadDir(path.Join(tmpDir, provider.Name+"-1"))
+	g.Expect(err).To(BeNil())
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		content, err := ioutil.ReadFile(path.Join(tmpDir, provider.Name+"-1", f.Name()))
+		g.Expect(err).To(BeNil())
+		logs += string(content)
+	}
+	g.Expect(logs).To(ContainSubstring("prepare 10.1.2.3 with token-of-10.1.2.3\n"))
+	g.Expect(logs).To(ContainSubstring("before with token-of-10.1.2.3\n"))
+	g.Expect(logs).To(ContainSubstring("test on 10.1.2.3 with token-of-10.1.2.3\n"))
+}
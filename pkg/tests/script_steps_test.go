@@ -0,0 +1,115 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestScriptSteps(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = config.Duration(300 * time.Second)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	provider := createProvider(testConfig, "a_provider")
+	provider.Instances = 1
+	provider.Shell = utils.ShellBash
+	provider.Scripts["start"] = config.Script{
+		{
+			Name:       "flaky",
+			Run:        `if [ ! -f "${MARKER}" ]; then touch "${MARKER}"; exit 1; fi`,
+			Retries:    2,
+			RetryDelay: config.Duration(10 * time.Millisecond),
+			Env:        []string{"MARKER=" + path.Join(tmpDir, "marker")},
+		},
+		{Name: "report", Run: "echo started after retry"},
+	}
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:    "pass",
+		Kind:    "shell",
+		Shell:   utils.ShellBash,
+		Timeout: config.Duration(15 * time.Second),
+		Run: config.Script{
+			{Name: "check", Run: "echo checking\nexit 3", ContinueOnError: true},
+			{Name: "verify", Run: "echo verified"},
+		},
+	}, &config.Execution{
+		Name:    "timeout",
+		Kind:    "shell",
+		Timeout: config.Duration(15 * time.Second),
+		Run: config.Script{
+			{Name: "wait", Run: "sleep 10", Timeout: config.Duration(time.Second)},
+			{Name: "skipped", Run: "echo not reached"},
+		},
+	})
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err.Error()).To(Equal("there is failed tests 1"))
+	g.Expect(report).NotTo(BeNil())
+
+	g.Expect(len(report.Suites[0].Suites)).To(Equal(2))
+	for _, execSuite := range report.Suites[0].Suites {
+		testCase := execSuite.Suites[0].TestCases[0]
+		if execSuite.Name == "pass" {
+			g.Expect(testCase.Failure).To(BeNil())
+			continue
+		}
+		g.Expect(testCase.Failure).NotTo(BeNil())
+		g.Expect(testCase.Failure.Contents).To(ContainSubstring(">>>>>>Step wait of timeout, attempt 1 of 1<<<<<<"))
+		g.Expect(testCase.Failure.Contents).NotTo(ContainSubstring(">>>>>>Step skipped"))
+	}
+
+	logs := map[string]string{}
+	files, err := ioutil.ReadDir(path.Join(tmpDir, provider.Name+"-1"))
+	g.Expect(err).To(BeNil())
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		content, err := ioutil.ReadFile(path.Join(tmpDir, provider.Name+"-1", f.Name()))
+		g.Expect(err).To(BeNil())
+		// Log files are prefixed with a sequence number.
+		logs[strings.SplitN(f.Name(), "-", 2)[1]] = string(content)
+	}
+	g.Expect(logs["start-flaky.log"]).To(ContainSubstring(">>>>>>Step flaky of start, attempt 2 of 3<<<<<<"))
+	g.Expect(logs["start-flaky.log"]).NotTo(ContainSubstring("attempt 3 of 3"))
+	g.Expect(logs["start-report.log"]).To(ContainSubstring("started after retry\n"))
+	g.Expect(logs["pass-run-check.log"]).To(ContainSubstring("checking\n"))
+	g.Expect(logs["pass-run-check.log"]).To(ContainSubstring("continue on error"))
+	g.Expect(logs["pass-run-verify.log"]).To(ContainSubstring("verified\n"))
+	g.Expect(logs["timeout-run-wait.log"]).To(ContainSubstring(">>>>>>Step wait of timeout, attempt 1 of 1<<<<<<"))
+	g.Expect(logs).NotTo(HaveKey("timeout-run-skipped.log"))
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/sirupsen/logrus"
@@ -49,7 +50,7 @@ func TestShellProvider(t *testing.T) {
 
 	testConfig := config.NewCloudTestConfig()
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -61,17 +62,17 @@ func TestShellProvider(t *testing.T) {
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:        "simple",
-		Timeout:     15,
-		PackageRoot: "./sample",
+		Timeout:     config.Duration(15 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
 	})
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:    "simple_tagged",
-		Timeout: 15,
+		Timeout: config.Duration(15 * time.Second),
 		Source: config.ExecutionSource{
 			Tags: []string{"basic"},
 		},
-		PackageRoot: "./sample",
+		PackageRoot: config.StringList{"./sample"},
 	})
 
 	testConfig.Reporting.JUnitReportFile = JunitReport
@@ -100,18 +101,18 @@ func TestShellProvider(t *testing.T) {
 
 func createProvider(testConfig *config.CloudTestConfig, name string) *config.ClusterProviderConfig {
 	provider := &config.ClusterProviderConfig{
-		Timeout:    100,
+		Timeout:    config.Duration(100 * time.Second),
 		Name:       name,
 		NodeCount:  1,
 		Kind:       "shell",
 		RetryCount: 1,
 		Instances:  2,
-		Scripts: map[string]string{
-			"config":  "echo ./.tests/config",
-			"start":   "echo started",
-			"prepare": "echo prepared",
-			"install": "echo installed",
-			"stop":    "echo stopped",
+		Scripts: map[string]config.Script{
+			"config":  config.NewScript("echo ./.tests/config"),
+			"start":   config.NewScript("echo started"),
+			"prepare": config.NewScript("echo prepared"),
+			"install": config.NewScript("echo installed"),
+			"stop":    config.NewScript("echo stopped"),
 		},
 		Enabled: true,
 	}
@@ -124,7 +125,7 @@ func TestInvalidProvider(t *testing.T) {
 
 	testConfig := config.NewCloudTestConfig()
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -136,8 +137,8 @@ func TestInvalidProvider(t *testing.T) {
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:        "simple",
-		Timeout:     2,
-		PackageRoot: "./sample",
+		Timeout:     config.Duration(2 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
 	})
 
 	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
@@ -153,7 +154,7 @@ func TestRequireEnvVars(t *testing.T) {
 
 	testConfig := config.NewCloudTestConfig()
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -169,8 +170,8 @@ func TestRequireEnvVars(t *testing.T) {
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:        "simple",
-		Timeout:     2,
-		PackageRoot: "./sample",
+		Timeout:     config.Duration(2 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
 	})
 
 	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
@@ -187,7 +188,7 @@ func TestRequireEnvVars_DEPS(t *testing.T) {
 
 	testConfig := config.NewCloudTestConfig()
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -219,8 +220,8 @@ func TestRequireEnvVars_DEPS(t *testing.T) {
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:        "simple",
-		Timeout:     2,
-		PackageRoot: "./sample",
+		Timeout:     config.Duration(2 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
 	})
 
 	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
@@ -235,7 +236,7 @@ func TestShellProviderShellTest(t *testing.T) {
 
 	testConfig := config.NewCloudTestConfig()
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -247,30 +248,30 @@ func TestShellProviderShellTest(t *testing.T) {
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:        "simple",
-		Timeout:     15,
-		PackageRoot: "./sample",
+		Timeout:     config.Duration(15 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
 	})
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:    "simple_shell",
-		Timeout: 150000,
+		Timeout: config.Duration(150000 * time.Second),
 		Kind:    "shell",
-		Run: strings.Join([]string{
+		Run: config.NewScript(strings.Join([]string{
 			"pwd",
 			"ls -la",
 			"echo $KUBECONFIG",
-		}, "\n"),
+		}, "\n")),
 	})
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:    "simple_shell_fail",
-		Timeout: 15,
+		Timeout: config.Duration(15 * time.Second),
 		Kind:    "shell",
-		Run: strings.Join([]string{
+		Run: config.NewScript(strings.Join([]string{
 			"pwd",
 			"ls -la",
 			"exit 1",
-		}, "\n"),
+		}, "\n")),
 	})
 
 	testConfig.Reporting.JUnitReportFile = JunitReport
@@ -313,7 +314,7 @@ func TestUnusedClusterShutdownByMonitor(t *testing.T) {
 	defer logKeeper.Stop()
 	testConfig := config.NewCloudTestConfig()
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -322,22 +323,22 @@ func TestUnusedClusterShutdownByMonitor(t *testing.T) {
 	testConfig.ConfigRoot = tmpDir
 	createProvider(testConfig, "a_provider")
 	p2 := createProvider(testConfig, "b_provider")
-	p2.TestDelay = 7
+	p2.TestDelay = config.Duration(7 * time.Second)
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:            "simple",
-		Timeout:         15,
-		PackageRoot:     "./sample",
+		Timeout:         config.Duration(15 * time.Second),
+		PackageRoot:     config.StringList{"./sample"},
 		ClusterSelector: []string{"a_provider"},
 	})
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:    "simple2",
-		Timeout: 15,
+		Timeout: config.Duration(15 * time.Second),
 		Source: config.ExecutionSource{
 			Tags: []string{"basic"},
 		},
-		PackageRoot:     "./sample",
+		PackageRoot:     config.StringList{"./sample"},
 		ClusterSelector: []string{"b_provider"},
 	})
 
@@ -372,7 +373,7 @@ func TestMultiClusterTest(t *testing.T) {
 
 	testConfig := config.NewCloudTestConfig()
 
-	testConfig.Timeout = 300
+	testConfig.Timeout = config.Duration(300 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -391,29 +392,29 @@ func TestMultiClusterTest(t *testing.T) {
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:            "simple",
-		Timeout:         15,
-		PackageRoot:     "./sample",
+		Timeout:         config.Duration(15 * time.Second),
+		PackageRoot:     config.StringList{"./sample"},
 		ClusterSelector: []string{"a_provider"},
 	})
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:    "simple2",
-		Timeout: 15,
+		Timeout: config.Duration(15 * time.Second),
 		Source: config.ExecutionSource{
 			Tags: []string{"interdomain"},
 		},
-		PackageRoot:     "./sample",
+		PackageRoot:     config.StringList{"./sample"},
 		ClusterCount:    2,
 		KubernetesEnv:   []string{"CFG1", "CFG2"},
 		ClusterSelector: []string{"a_provider", "b_provider"},
 	})
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:    "simple3",
-		Timeout: 15,
+		Timeout: config.Duration(15 * time.Second),
 		Source: config.ExecutionSource{
 			Tags: []string{"interdomain"},
 		},
-		PackageRoot:     "./sample",
+		PackageRoot:     config.StringList{"./sample"},
 		ClusterCount:    2,
 		KubernetesEnv:   []string{"CFG1", "CFG2"},
 		ClusterSelector: []string{"c_provider", "d_provider"},
@@ -446,7 +447,7 @@ func TestGlobalTimeout(t *testing.T) {
 	g := NewWithT(t)
 
 	testConfig := config.NewCloudTestConfig()
-	testConfig.Timeout = 3
+	testConfig.Timeout = config.Duration(3 * time.Second)
 
 	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
 	defer utils.ClearFolder(tmpDir, false)
@@ -457,14 +458,14 @@ func TestGlobalTimeout(t *testing.T) {
 
 	testConfig.Executions = append(testConfig.Executions, &config.Execution{
 		Name:        "simple",
-		Timeout:     15,
-		PackageRoot: "./sample",
+		Timeout:     config.Duration(15 * time.Second),
+		PackageRoot: config.StringList{"./sample"},
 	})
 
 	testConfig.Reporting.JUnitReportFile = JunitReport
 
 	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
-	g.Expect(err.Error()).To(Equal("global timeout elapsed: 3 seconds"))
+	g.Expect(err.Error()).To(Equal("global timeout elapsed: 3s"))
 
 	g.Expect(report).NotTo(BeNil())
 
@@ -0,0 +1,93 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/reporting"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestShellSuiteExecution(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = config.Duration(300 * time.Second)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	createProvider(testConfig, "a_provider").Instances = 1
+	createProvider(testConfig, "b_provider").Instances = 1
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:            "suite",
+		Kind:            "shell",
+		Timeout:         config.Duration(15 * time.Second),
+		Scripts:         config.StringList{"./sample/shell"},
+		ClusterSelector: []string{"a_provider", "b_provider"},
+	}, &config.Execution{
+		Name:            "slow",
+		Kind:            "shell",
+		Timeout:         config.Duration(15 * time.Second),
+		Scripts:         config.StringList{"./sample/shell/*.sh"},
+		ClusterSelector: []string{"a_provider"},
+		Source: config.ExecutionSource{
+			Tags: []string{"slow"},
+		},
+	})
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err.Error()).To(Equal("there is failed tests 3"))
+	g.Expect(report).NotTo(BeNil())
+
+	// Test cases by execution and cluster suite names.
+	testCases := map[string][]*reporting.TestCase{}
+	for _, execSuite := range report.Suites[0].Suites {
+		for _, clusterSuite := range execSuite.Suites {
+			testCases[execSuite.Name+"/"+clusterSuite.Name] = append(testCases[execSuite.Name+"/"+clusterSuite.Name], clusterSuite.TestCases...)
+		}
+	}
+	g.Expect(len(testCases["suite/a_provider-b_provider"])).To(Equal(1))
+	passed := testCases["suite/a_provider-b_provider"][0]
+	g.Expect(passed.Name).To(Equal("sample/shell/pass.sh"))
+	g.Expect(passed.Failure).To(BeNil())
+	g.Expect(passed.Cluster).To(Equal("a_provider-1_b_provider-1"))
+
+	for _, suite := range []string{"suite/a_provider", "suite/b_provider"} {
+		g.Expect(len(testCases[suite])).To(Equal(1))
+		g.Expect(testCases[suite][0].Name).To(Equal("sample/shell/fail.sh"))
+		g.Expect(testCases[suite][0].Failure).NotTo(BeNil())
+	}
+
+	g.Expect(len(testCases["slow/a_provider"])).To(Equal(1))
+	slow := testCases["slow/a_provider"][0]
+	g.Expect(slow.Name).To(Equal("sample/shell/slow.sh"))
+	g.Expect(slow.Failure).NotTo(BeNil())
+	g.Expect(slow.Properties[0].Value).To(Equal("slow"))
+}
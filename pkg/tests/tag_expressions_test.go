@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTagExpressionsExecution(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = config.Duration(300 * time.Second)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	p := createProvider(testConfig, "a_provider")
+	p.Instances = 1
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "tags",
+		Timeout:     config.Duration(15 * time.Second),
+		PackageRoot: config.StringList{"./sample/tags"},
+		Source: config.ExecutionSource{
+			Tags: []string{"basic && !slow", "nightly"},
+		},
+	})
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err).To(BeNil())
+	g.Expect(report).NotTo(BeNil())
+
+	tags := map[string]string{}
+	for _, testCase := range report.Suites[0].Suites[0].Suites[0].TestCases {
+		g.Expect(testCase.Failure).To(BeNil())
+		g.Expect(len(testCase.Properties)).To(Equal(1))
+		g.Expect(testCase.Properties[0].Name).To(Equal("tags"))
+		tags[testCase.Name] = testCase.Properties[0].Value
+	}
+	g.Expect(tags).To(Equal(map[string]string{
+		"TestBasic":         "basic",
+		"TestFastOrNightly": "nightly",
+	}))
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/reporting"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTapShellExecution(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = config.Duration(300 * time.Second)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	p := createProvider(testConfig, "a_provider")
+	p.Instances = 1
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:    "tap",
+		Kind:    "shell",
+		Format:  "tap",
+		Timeout: config.Duration(15 * time.Second),
+		Scripts: config.StringList{"./sample/tap"},
+	})
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err.Error()).To(Equal("there is failed tests 1"))
+	g.Expect(report).NotTo(BeNil())
+
+	testCases := map[string]*reporting.TestCase{}
+	for _, testCase := range report.Suites[0].Suites[0].Suites[0].TestCases {
+		testCases[testCase.Name] = testCase
+	}
+
+	passed := testCases["sample/tap/pass.sh"]
+	g.Expect(passed.Failure).To(BeNil())
+	g.Expect(len(passed.TestCases)).To(Equal(4))
+	g.Expect(passed.TestCases[0].Name).To(Equal("sample/tap/pass.sh/1 - cluster is available"))
+	g.Expect(passed.TestCases[0].SkipMessage).To(BeNil())
+	g.Expect(passed.TestCases[1].Name).To(Equal("sample/tap/pass.sh/2 - pods are_running"))
+	g.Expect(passed.TestCases[1].SkipMessage.Message).To(Equal("SKIP no pods in shell cluster"))
+	g.Expect(passed.TestCases[2].SkipMessage.Message).To(Equal("TODO not implemented\n# diagnostic of todo point"))
+	g.Expect(passed.TestCases[2].Failure).To(BeNil())
+	g.Expect(passed.TestCases[3].Name).To(Equal("sample/tap/pass.sh/4"))
+
+	failed := testCases["sample/tap/fail.sh"]
+	g.Expect(failed.Failure).NotTo(BeNil())
+	g.Expect(failed.Failure.Contents).To(ContainSubstring("not ok 2 - second"))
+	g.Expect(len(failed.TestCases)).To(Equal(3))
+	g.Expect(failed.TestCases[0].Failure).To(BeNil())
+	g.Expect(failed.TestCases[1].Failure.Contents).To(Equal("# expected: ready\n#   actual: pending\n"))
+	g.Expect(failed.TestCases[2].Failure).To(BeNil())
+
+	nested := testCases["sample/tap/nested.sh"]
+	g.Expect(nested.Failure).To(BeNil())
+	g.Expect(len(nested.TestCases)).To(Equal(2))
+	g.Expect(nested.TestCases[0].Name).To(Equal("sample/tap/nested.sh/1 - deployment"))
+	g.Expect(len(nested.TestCases[0].TestCases)).To(Equal(2))
+	g.Expect(nested.TestCases[0].TestCases[1].Name).To(Equal("sample/tap/nested.sh/1 - deployment/2 - pods are ready"))
+	g.Expect(nested.TestCases[1].TestCases).To(BeEmpty())
+}
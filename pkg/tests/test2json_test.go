@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denis-tingajkin/cloudtest/pkg/commands"
+	"github.com/denis-tingajkin/cloudtest/pkg/config"
+	"github.com/denis-tingajkin/cloudtest/pkg/reporting"
+	"github.com/denis-tingajkin/cloudtest/pkg/utils"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestStructuredGoTestResults(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = config.Duration(300 * time.Second)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	defer utils.ClearFolder(tmpDir, false)
+	g.Expect(err).To(BeNil())
+
+	testConfig.ConfigRoot = tmpDir
+	p := createProvider(testConfig, "a_provider")
+	p.Instances = 1
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "results",
+		Timeout:     config.Duration(15 * time.Second),
+		PackageRoot: config.StringList{"./sample/results"},
+	})
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &testValidationFactory{}, &commands.Arguments{})
+	g.Expect(err.Error()).To(Equal("there is failed tests 1"))
+	g.Expect(report).NotTo(BeNil())
+
+	testCases := map[string]*reporting.TestCase{}
+	for _, testCase := range report.Suites[0].Suites[0].Suites[0].TestCases {
+		testCases[testCase.Name] = testCase
+	}
+	g.Expect(len(testCases)).To(Equal(3))
+
+	skipped := testCases["TestSkipped"]
+	g.Expect(skipped.Failure).To(BeNil())
+	g.Expect(skipped.SkipMessage).NotTo(BeNil())
+	g.Expect(skipped.SkipMessage.Message).To(ContainSubstring("not supported here"))
+
+	parallel := testCases["TestParallel"]
+	g.Expect(parallel.Failure).NotTo(BeNil())
+	g.Expect(len(parallel.TestCases)).To(Equal(2))
+	g.Expect(parallel.TestCases[0].Name).To(Equal("TestParallel/first"))
+	g.Expect(parallel.TestCases[0].Failure).To(BeNil())
+	second := parallel.TestCases[1]
+	g.Expect(second.Name).To(Equal("TestParallel/second"))
+	g.Expect(second.Failure).NotTo(BeNil())
+	g.Expect(second.Failure.Contents).To(ContainSubstring("failed second"))
+	g.Expect(strings.Count(second.Failure.Contents, "output of second")).To(Equal(3))
+	g.Expect(second.Failure.Contents).NotTo(ContainSubstring("output of first"))
+
+	nested := testCases["TestNested"]
+	g.Expect(nested.Failure).To(BeNil())
+	g.Expect(nested.SkipMessage).To(BeNil())
+	g.Expect(len(nested.TestCases)).To(Equal(1))
+	g.Expect(len(nested.TestCases[0].TestCases)).To(Equal(1))
+	child := nested.TestCases[0].TestCases[0]
+	g.Expect(child.Name).To(Equal("TestNested/parent/child"))
+	g.Expect(child.SkipMessage.Message).To(ContainSubstring("skipped child"))
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var envNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ReadEnvFile - read a .env file and return its variables as KEY=VALUE list. Empty lines and # comments are skipped,
+// an optional 'export' prefix is supported, single or double quotes around value are removed.
+func ReadEnvFile(fileName string) ([]string, error) {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for idx, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		pos := strings.Index(line, "=")
+		if pos <= 0 || !envNamePattern.MatchString(strings.TrimSpace(line[:pos])) {
+			return nil, errors.Errorf("%s:%d: KEY=VALUE is expected", fileName, idx+1)
+		}
+		key, value := strings.TrimSpace(line[:pos]), strings.TrimSpace(line[pos+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		result = append(result, key+"="+value)
+	}
+	return result, nil
+}
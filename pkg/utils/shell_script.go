@@ -0,0 +1,144 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ShellBash - a shell option value to execute scripts by one bash process as a whole, instead of line by line.
+const ShellBash = "bash"
+
+var (
+	scriptArgument = regexp.MustCompile(`\$\(([^()\s]+)\)`)
+	heredocStart   = regexp.MustCompile(`<<-?\s*['"]?(\w+)['"]?`)
+	blockOpen      = map[string]bool{"if": true, "case": true, "do": true, "{": true, "(": true}
+	blockClose     = map[string]bool{"fi": true, "esac": true, "done": true, "}": true, ")": true}
+)
+
+// RunScript - run multi line script in one bash process with "set -euo pipefail", so pipes, redirects, cd, export,
+// conditions and heredocs work same way as in a script file. Known $(arg) arguments are substituted, other $(...) and
+// ${VAR} are left to bash. Start and end of every logical step of script are written to output.
+func RunScript(context context.Context, script, dir string, logger func(str string), writer *bufio.Writer, env []string, args map[string]string, returnStdout bool) (string, error) {
+	bashScript := strings.Builder{}
+	_, _ = bashScript.WriteString("set -euo pipefail\n")
+	if !returnStdout {
+		// Step markers are written to stderr, it is merged into stdout to keep an order of output.
+		_, _ = bashScript.WriteString("exec 2>&1\n")
+	}
+	for _, step := range ScriptSteps(script) {
+		title := strings.SplitN(step, "\n", 2)[0]
+		_, _ = bashScript.WriteString(fmt.Sprintf("printf '%%s\\n' %s >&2\n%s\nprintf '%%s\\n' %s >&2\n",
+			shellQuote(">>>>>>Running: "+title+":<<<<<<"), SubstituteArguments(step, args), shellQuote(">>>>>>Done: "+title+"<<<<<<")))
+	}
+
+	proc, err := ExecProc(context, dir, []string{"bash", "-c", bashScript.String()}, append([]string{}, env...))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to run bash")
+	}
+	builder := strings.Builder{}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	processOutput(proc.Stdout, writer, logger, "StdOut", &builder, returnStdout, &wg)
+	processOutput(proc.Stderr, writer, logger, "StdErr", nil, false, &wg)
+	wg.Wait()
+	if code := proc.ExitCode(); code != 0 {
+		return "", errors.Errorf("failed to run script with bash ExitCode: %v", code)
+	}
+	if returnStdout {
+		return builder.String(), nil
+	}
+	return "", nil
+}
+
+// SubstituteArguments - substitute $(arg) arguments found in args, all other text is kept as is.
+func SubstituteArguments(value string, args map[string]string) string {
+	return scriptArgument.ReplaceAllStringFunc(value, func(s string) string {
+		if argValue, ok := args[scriptArgument.FindStringSubmatch(s)[1]]; ok {
+			return argValue
+		}
+		return s
+	})
+}
+
+// ScriptSteps - split script into logical steps, a step is a command with its line continuations, heredocs and
+// compound command blocks. Comments and empty lines are dropped. If script structure could not be recognized, it is
+// returned as a single step.
+func ScriptSteps(script string) []string {
+	script = strings.TrimSpace(script)
+	var steps, current []string
+	depth := 0
+	heredoc := ""
+	for _, line := range strings.Split(script, "\n") {
+		current = append(current, line)
+		trimmed := strings.TrimSpace(line)
+		if heredoc != "" {
+			if trimmed == heredoc {
+				heredoc = ""
+			}
+		} else {
+			if m := heredocStart.FindStringSubmatch(line); m != nil {
+				heredoc = m[1]
+			}
+			depth += blockDepth(trimmed)
+		}
+		if depth < 0 {
+			return []string{script}
+		}
+		if heredoc != "" || depth > 0 || strings.HasSuffix(trimmed, "\\") ||
+			strings.HasSuffix(trimmed, "|") || strings.HasSuffix(trimmed, "&&") {
+			continue
+		}
+		if step := strings.TrimSpace(strings.Join(current, "\n")); step != "" && !strings.HasPrefix(step, "#") {
+			steps = append(steps, step)
+		}
+		current = nil
+	}
+	if len(current) > 0 {
+		return []string{script}
+	}
+	return steps
+}
+
+// blockDepth - return a change of compound command nesting made by line.
+func blockDepth(line string) int {
+	depth := 0
+	for _, token := range strings.Fields(line) {
+		if strings.HasPrefix(token, "#") {
+			break
+		}
+		token = strings.TrimSuffix(token, ";")
+		if blockOpen[token] {
+			depth++
+		} else if blockClose[token] {
+			depth--
+		}
+	}
+	return depth
+}
+
+// shellQuote - quote value as a single bash word.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
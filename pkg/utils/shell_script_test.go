@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bufio"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestScriptSteps(t *testing.T) {
+	g := gomega.NewWithT(t)
+	g.Expect(ScriptSteps(`
+# prepare
+cd /tmp
+export A=1 \
+  B=2
+
+if [ -n "$A" ]; then
+  echo a | tr a b
+fi
+cat <<EOF > file
+if
+EOF
+kubectl get pods |
+  grep Running
+for i in 1 2; do echo $i; done
+`)).Should(gomega.Equal([]string{
+		"cd /tmp",
+		"export A=1 \\\n  B=2",
+		"if [ -n \"$A\" ]; then\n  echo a | tr a b\nfi",
+		"cat <<EOF > file\nif\nEOF",
+		"kubectl get pods |\n  grep Running",
+		"for i in 1 2; do echo $i; done",
+	}))
+
+	// Not recognized structure is a single step.
+	g.Expect(ScriptSteps("echo if\necho done")).Should(gomega.Equal([]string{"echo if\necho done"}))
+	g.Expect(ScriptSteps("echo a\nfi")).Should(gomega.Equal([]string{"echo a\nfi"}))
+}
+
+func TestSubstituteArguments(t *testing.T) {
+	g := gomega.NewWithT(t)
+	g.Expect(SubstituteArguments("ls $(artifacts-dir) $(date +%s) ${HOME}", map[string]string{"artifacts-dir": "/tmp/a"})).
+		Should(gomega.Equal("ls /tmp/a $(date +%s) ${HOME}"))
+}
+
+func TestRunScript(t *testing.T) {
+	g := gomega.NewWithT(t)
+	output := &strings.Builder{}
+	_, err := RunScript(context.Background(), `
+cd /
+export VALUE=$(name)
+if [ "$(pwd)" = "/" ]; then
+  echo "in root ${VALUE}" | tr a-z A-Z
+fi
+cat <<EOF
+heredoc $ENV_VALUE
+EOF
+`, "", func(s string) {}, bufio.NewWriter(output), []string{"ENV_VALUE=env"}, map[string]string{"name": "value"}, false)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(output.String()).Should(gomega.Equal(`>>>>>>Running: cd /:<<<<<<
+>>>>>>Done: cd /<<<<<<
+>>>>>>Running: export VALUE=$(name):<<<<<<
+>>>>>>Done: export VALUE=$(name)<<<<<<
+>>>>>>Running: if [ "$(pwd)" = "/" ]; then:<<<<<<
+IN ROOT VALUE
+>>>>>>Done: if [ "$(pwd)" = "/" ]; then<<<<<<
+>>>>>>Running: cat <<EOF:<<<<<<
+heredoc env
+>>>>>>Done: cat <<EOF<<<<<<
+`))
+
+	output.Reset()
+	_, err = RunScript(context.Background(), "true | cat\necho next\nfalse | cat\necho skipped", "", func(s string) {},
+		bufio.NewWriter(output), nil, nil, false)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+	g.Expect(output.String()).Should(gomega.ContainSubstring("next"))
+	g.Expect(output.String()).ShouldNot(gomega.ContainSubstring("skipped"))
+
+	result, err := RunScript(context.Background(), "echo a >&2\necho b", "", func(s string) {},
+		bufio.NewWriter(&strings.Builder{}), nil, nil, true)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(result).Should(gomega.Equal("b\n"))
+}
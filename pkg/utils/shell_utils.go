@@ -21,6 +21,25 @@ func ParseVariable(variable string) (string, string, error) {
 	return variable[:pos], variable[pos+1:], nil
 }
 
+// MaskEnvValues - replace values of passed environment variables found inside value with ****.
+func MaskEnvValues(value string, envNames []string) string {
+	var secrets []string
+	for _, name := range envNames {
+		secrets = append(secrets, os.Getenv(name))
+	}
+	return MaskValues(value, secrets)
+}
+
+// MaskValues - replace secret values found inside value with ****.
+func MaskValues(value string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			value = strings.Replace(value, secret, "****", -1)
+		}
+	}
+	return value
+}
+
 // ParseCommandLine - parses command line with support of "" and escaping.
 func ParseCommandLine(cmdLine string) []string {
 	pos := 0
@@ -68,73 +87,6 @@ func ParseCommandLine(cmdLine string) []string {
 	return result
 }
 
-// SubstituteVariable - perform a substitution of all ${var} $(arg) in passed string and return substitution results and error
-func SubstituteVariable(variable string, vars, args map[string]string) (string, error) {
-	pos := 0
-	result := strings.Builder{}
-
-	count := len(variable)
-
-	for pos < count {
-		charAt := variable[pos]
-
-		if charAt == '$' {
-			if pos+1 < count {
-				// We have more symbols to check
-				nextChar := variable[pos+1]
-
-				if nextChar == '{' {
-					// This is variable substitution
-					pos += 2
-					var varName string
-					varName, pos = readString(pos, count, variable, '}')
-
-					// We found variable or reached end of string
-					if varValue, ok := vars[varName]; ok {
-						_, _ = result.WriteString(varValue)
-					} else {
-						return "", errors.Errorf("failed to find variable %v in passed variables", varName)
-					}
-				} else if nextChar == '(' {
-					// This is parameter substitution
-					pos += 2
-					var varName string
-					varName, pos = readString(pos, count, variable, ')')
-
-					// We found variable or reached end of string
-					if argValue, ok := args[varName]; ok {
-						_, _ = result.WriteString(argValue)
-					} else {
-						return "", errors.Errorf("failed to find argument %v in passed arguments", varName)
-					}
-				}
-			} else {
-				// End of string just add symbol to result
-				_ = result.WriteByte(charAt)
-			}
-		} else {
-			_ = result.WriteByte(charAt)
-		}
-
-		pos++
-	}
-	return result.String(), nil
-}
-
-func readString(pos, count int, variable string, delim uint8) (string, int) {
-	varName := strings.Builder{}
-	for pos < count {
-		tChar := variable[pos]
-		if tChar == delim {
-			break
-		} else {
-			_ = varName.WriteByte(tChar)
-		}
-		pos++
-	}
-	return varName.String(), pos
-}
-
 func readStringEscaping(pos, count int, variable string, delim uint8) (string, int) {
 	varName := strings.Builder{}
 	for pos < count {
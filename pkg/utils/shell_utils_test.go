@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -21,3 +22,16 @@ func TestProcessOutputShouldNotLostOutput(t *testing.T) {
 		assert.Expect(strings.TrimSpace(output)).Should(gomega.Equal(expected))
 	}
 }
+
+func TestMaskEnvValues(t *testing.T) {
+	assert := gomega.NewWithT(t)
+	assert.Expect(os.Setenv("CLOUDTEST_MASK_TOKEN", "secret")).Should(gomega.BeNil())
+	assert.Expect(os.Setenv("CLOUDTEST_MASK_EMPTY", "")).Should(gomega.BeNil())
+	defer func() {
+		_ = os.Unsetenv("CLOUDTEST_MASK_TOKEN")
+		_ = os.Unsetenv("CLOUDTEST_MASK_EMPTY")
+	}()
+
+	assert.Expect(MaskEnvValues("token=secret", []string{"CLOUDTEST_MASK_TOKEN", "CLOUDTEST_MASK_EMPTY"})).Should(gomega.Equal("token=****"))
+	assert.Expect(MaskValues("user=admin password=secret", []string{"secret", ""})).Should(gomega.Equal("user=admin password=****"))
+}
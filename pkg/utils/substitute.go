@@ -0,0 +1,213 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SubstituteVariable - perform a substitution of all ${var} $(arg) in passed string and return substitution results
+// and error. ${VAR:-default} is replaced with default if variable is not set or empty, ${VAR:?message} fails with
+// message in this case, $$ is a literal $. Functions $(random:N), $(now:layout), $(lower:text), $(upper:text) return
+// a random string of N symbols, current time formatted with go layout and a text in lower or upper case,
+// $(env-file:path) returns a content of file without trailing new lines and $(env-file:path:NAME) a value of variable
+// defined in .env file. Names, defaults, messages and function arguments could contain substitutions too.
+func SubstituteVariable(variable string, vars, args map[string]string) (string, error) {
+	result, err := substitute(variable, vars, args)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to substitute %q", variable)
+	}
+	return result, nil
+}
+
+func substitute(variable string, vars, args map[string]string) (string, error) {
+	result := strings.Builder{}
+	for pos := 0; pos < len(variable); pos++ {
+		charAt := variable[pos]
+		if charAt != '$' || pos+1 == len(variable) {
+			_ = result.WriteByte(charAt)
+			continue
+		}
+		switch nextChar := variable[pos+1]; nextChar {
+		case '$':
+			_ = result.WriteByte('$')
+			pos++
+		case '{', '(':
+			end, err := closingBracket(variable, pos+1)
+			if err != nil {
+				return "", err
+			}
+			var value string
+			if nextChar == '{' {
+				value, err = substituteVar(variable[pos+2:end], vars, args)
+			} else {
+				value, err = substituteArg(variable[pos+2:end], vars, args)
+			}
+			if err != nil {
+				return "", err
+			}
+			_, _ = result.WriteString(value)
+			pos = end
+		default:
+			_ = result.WriteByte(charAt)
+		}
+	}
+	return result.String(), nil
+}
+
+// closingBracket - return position of bracket closing the one at open position, nested substitutions are skipped.
+func closingBracket(variable string, open int) (int, error) {
+	closing := map[byte]byte{'{': '}', '(': ')'}
+	stack := []byte{closing[variable[open]]}
+	for pos := open + 1; pos < len(variable); pos++ {
+		charAt := variable[pos]
+		switch {
+		case charAt == '$' && pos+1 < len(variable) && variable[pos+1] == '$':
+			pos++
+		case charAt == '$' && pos+1 < len(variable) && closing[variable[pos+1]] != 0:
+			stack = append(stack, closing[variable[pos+1]])
+			pos++
+		case closing[charAt] == stack[len(stack)-1]:
+			// A plain bracket of same kind, like a parenthesis inside of $(...)
+			stack = append(stack, stack[len(stack)-1])
+		case charAt == stack[len(stack)-1]:
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return pos, nil
+			}
+		}
+	}
+	return 0, errors.Errorf("no closing %c for $%c at position %d", stack[0], variable[open], open)
+}
+
+// splitOperator - split expression into a name and the rest starting from first colon outside of nested substitutions.
+func splitOperator(expr string) (string, string) {
+	for pos := 0; pos < len(expr); pos++ {
+		switch {
+		case expr[pos] == ':':
+			return expr[:pos], expr[pos:]
+		case expr[pos] == '$' && pos+1 < len(expr) && (expr[pos+1] == '{' || expr[pos+1] == '('):
+			if end, err := closingBracket(expr, pos+1); err == nil {
+				pos = end
+			}
+		}
+	}
+	return expr, ""
+}
+
+func substituteVar(expr string, vars, args map[string]string) (string, error) {
+	rawName, operator := splitOperator(expr)
+	name, err := substitute(rawName, vars, args)
+	if err != nil {
+		return "", err
+	}
+	value, ok := vars[name]
+	switch {
+	case operator == "":
+		if !ok {
+			return "", errors.Errorf("failed to find variable %v in passed variables", name)
+		}
+		return value, nil
+	case strings.HasPrefix(operator, ":-"):
+		if !ok || value == "" {
+			return substitute(operator[2:], vars, args)
+		}
+		return value, nil
+	case strings.HasPrefix(operator, ":?"):
+		if !ok || value == "" {
+			message, err := substitute(operator[2:], vars, args)
+			if err != nil {
+				return "", err
+			}
+			if message == "" {
+				message = "variable is not set"
+			}
+			return "", errors.Errorf("%s: %s", name, message)
+		}
+		return value, nil
+	}
+	return "", errors.Errorf("unsupported expansion ${%s}, only ${VAR:-default} and ${VAR:?message} are supported", expr)
+}
+
+func substituteArg(expr string, vars, args map[string]string) (string, error) {
+	if value, ok := args[expr]; ok {
+		return value, nil
+	}
+	rawName, operator := splitOperator(expr)
+	name, err := substitute(rawName, vars, args)
+	if err != nil {
+		return "", err
+	}
+	if operator == "" {
+		if value, ok := args[name]; ok {
+			return value, nil
+		}
+		return "", errors.Errorf("failed to find argument %v in passed arguments", name)
+	}
+	param, err := substitute(operator[1:], vars, args)
+	if err != nil {
+		return "", err
+	}
+	switch name {
+	case "random":
+		size, err := strconv.Atoi(param)
+		if err != nil || size <= 0 {
+			return "", errors.Errorf("$(random:%s): a positive length is expected", param)
+		}
+		value := NewRandomStr(size + 1)
+		if len(value) < size {
+			return "", errors.Errorf("$(random:%s): failed to generate a random string", param)
+		}
+		return value[:size], nil
+	case "now":
+		return time.Now().Format(param), nil
+	case "lower":
+		return strings.ToLower(param), nil
+	case "upper":
+		return strings.ToUpper(param), nil
+	case "env-file":
+		return envFileValue(param)
+	}
+	return "", errors.Errorf("unknown function %s, random, now, lower, upper and env-file are supported", name)
+}
+
+// envFileValue - return content of file or a value of variable of .env file, if path is followed by :NAME.
+func envFileValue(param string) (string, error) {
+	if idx := strings.LastIndex(param, ":"); idx > 0 && envNamePattern.MatchString(param[idx+1:]) {
+		fileName, name := param[:idx], param[idx+1:]
+		env, err := ReadEnvFile(fileName)
+		if err != nil {
+			return "", err
+		}
+		for _, e := range env {
+			if key, value, _ := ParseVariable(e); key == name {
+				return value, nil
+			}
+		}
+		return "", errors.Errorf("%s: variable %s is not defined", fileName, name)
+	}
+	content, err := ioutil.ReadFile(param)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
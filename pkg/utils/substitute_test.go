@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestSubstituteVariable(t *testing.T) {
+	vars := map[string]string{"HOME": "/home/user", "EMPTY": "", "PREFIX": "API", "API_HOST": "api.local"}
+	args := map[string]string{"cluster-name": "kind-1", "device.kind-1.ip": "10.0.0.1"}
+	for value, expected := range map[string]string{
+		"${HOME}/.kube $(cluster-name)":         "/home/user/.kube kind-1",
+		"${MISSING:-default} ${EMPTY:-empty}":   "default empty",
+		"${HOME:-default}":                      "/home/user",
+		"${MISSING:-${EMPTY:-$(cluster-name)}}": "kind-1",
+		"${${PREFIX}_HOST}":                     "api.local",
+		"$(device.$(cluster-name).ip)":          "10.0.0.1",
+		"price $$5 $${HOME} $$(cluster-name)":   "price $5 ${HOME} $(cluster-name)",
+		"awk '{print $1}' $":                    "awk '{print $1}' $",
+		"$(lower:KIND-${PREFIX})":               "kind-api",
+		"$(upper:$(cluster-name))":              "KIND-1",
+		"$(now:2006)":                           time.Now().Format("2006"),
+	} {
+		g := gomega.NewWithT(t)
+		result, err := SubstituteVariable(value, vars, args)
+		g.Expect(err).Should(gomega.BeNil(), value)
+		g.Expect(result).Should(gomega.Equal(expected), value)
+	}
+}
+
+func TestSubstituteVariableFunctions(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer ClearFolder(tmpDir, false)
+
+	token := filepath.Join(tmpDir, "token")
+	g.Expect(ioutil.WriteFile(token, []byte("secret\n"), 0600)).Should(gomega.BeNil())
+	envFile := filepath.Join(tmpDir, ".env")
+	g.Expect(ioutil.WriteFile(envFile, []byte("# Cluster\nexport REGION=\"us-east\"\nZONE='a'\n"), 0600)).Should(gomega.BeNil())
+
+	result, err := SubstituteVariable("$(env-file:"+token+") $(env-file:"+envFile+":REGION)-$(env-file:${DIR}/.env:ZONE)",
+		map[string]string{"DIR": tmpDir}, nil)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(result).Should(gomega.Equal("secret us-east-a"))
+
+	result, err = SubstituteVariable("$(random:7)", nil, nil)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(result).Should(gomega.MatchRegexp("^[0-9a-f]{7}$"))
+}
+
+func TestSubstituteVariableErrors(t *testing.T) {
+	for value, expected := range map[string]string{
+		"a ${MISSING} b":            `failed to substitute "a ${MISSING} b": failed to find variable MISSING in passed variables`,
+		"$(missing)":                `failed to substitute "$(missing)": failed to find argument missing in passed arguments`,
+		"${TOKEN:?token is needed}": `failed to substitute "${TOKEN:?token is needed}": TOKEN: token is needed`,
+		"${EMPTY:?}":                `failed to substitute "${EMPTY:?}": EMPTY: variable is not set`,
+		"${HOME:+alt}":              `failed to substitute "${HOME:+alt}": unsupported expansion ${HOME:+alt}, only ${VAR:-default} and ${VAR:?message} are supported`,
+		"${HOME":                    `failed to substitute "${HOME": no closing } for ${ at position 1`,
+		"$(random:x)":               `failed to substitute "$(random:x)": $(random:x): a positive length is expected`,
+		"$(base64:x)":               `failed to substitute "$(base64:x)": unknown function base64, random, now, lower, upper and env-file are supported`,
+	} {
+		g := gomega.NewWithT(t)
+		_, err := SubstituteVariable(value, map[string]string{"HOME": "/home/user", "EMPTY": ""}, nil)
+		g.Expect(err).Should(gomega.MatchError(expected), value)
+	}
+}
+
+func TestReadEnvFile(t *testing.T) {
+	g := gomega.NewWithT(t)
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	g.Expect(err).Should(gomega.BeNil())
+	defer ClearFolder(tmpDir, false)
+
+	envFile := filepath.Join(tmpDir, ".env")
+	g.Expect(ioutil.WriteFile(envFile, []byte("A=1\n\n# comment\nB = \"two words\"\nC=${A}\n"), 0600)).Should(gomega.BeNil())
+	env, err := ReadEnvFile(envFile)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(env).Should(gomega.Equal([]string{"A=1", "B=two words", "C=${A}"}))
+
+	g.Expect(ioutil.WriteFile(envFile, []byte("A=1\nnot a variable\n"), 0600)).Should(gomega.BeNil())
+	_, err = ReadEnvFile(envFile)
+	g.Expect(err).Should(gomega.MatchError(envFile + ":2: KEY=VALUE is expected"))
+}